@@ -0,0 +1,101 @@
+package nest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RevokeURL is the URL access tokens are deauthorized at
+const RevokeURL = "https://api.home.nest.com/oauth2/access_tokens"
+
+// TokenState is the state of an access token, as reported by Validate
+type TokenState int
+
+// Access token states
+const (
+	TokenUnknown TokenState = iota
+	TokenValid
+	TokenExpired
+	TokenRevoked
+)
+
+func (s TokenState) String() string {
+	switch s {
+	case TokenValid:
+		return "valid"
+	case TokenExpired:
+		return "expired"
+	case TokenRevoked:
+		return "revoked"
+	}
+
+	return "unknown"
+}
+
+// RevokeToken deauthorizes the connection's access token, after which it can't be used again
+func (n *Connection) RevokeToken() error {
+	// Error checking
+	if strings.Trim(n.AccessToken, " ") == "" {
+		return errors.New("Access token must not be empty")
+	}
+
+	url := fmt.Sprintf("%s/%s", RevokeURL, n.AccessToken)
+
+	// URL to use for tests
+	if n.testURL != "" {
+		url = fmt.Sprintf("%s/oauth2/access_tokens/%s", n.rootURL(), n.AccessToken)
+	}
+
+	_, err := n.execute(url, "DELETE", nil)
+
+	return err
+}
+
+// Validate performs a cheap authenticated read to check whether the connection's access
+// token can still be used. An expired token is told apart from a revoked one by the
+// error Nest responds with. Failures that say nothing about the token, like network
+// errors or a 403 for a permission the token doesn't have, are returned along with
+// TokenUnknown.
+func (n *Connection) Validate() (TokenState, error) {
+	// Error checking
+	if strings.Trim(n.AccessToken, " ") == "" {
+		return TokenUnknown, errors.New("Access token must not be empty")
+	}
+
+	if !n.TokenExpiry.IsZero() && time.Now().After(n.TokenExpiry) {
+		return TokenExpired, nil
+	}
+
+	// A cached response says nothing about the token now
+	_, err := n.send(fmt.Sprintf("%s/metadata", n.rootURL()), "GET", nil)
+	if err != nil {
+		state := tokenState(err)
+		if state == TokenUnknown {
+			return state, err
+		}
+
+		return state, nil
+	}
+
+	return TokenValid, nil
+}
+
+// tokenState works out what a failed authenticated read says about the access token
+func tokenState(err error) TokenState {
+	apiErr := &APIError{}
+	if !errors.As(err, &apiErr) || !errors.Is(apiErr, ErrUnauthorized) {
+		return TokenUnknown
+	}
+
+	switch {
+	case apiErr.contains("expired"):
+		return TokenExpired
+	case apiErr.StatusCode == 401, apiErr.contains("unauthorized"), apiErr.contains("token"), apiErr.contains("revoked"):
+		return TokenRevoked
+	}
+
+	// Forbidden for a reason other than the token, like a missing permission
+	return TokenUnknown
+}
@@ -0,0 +1,184 @@
+package nest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRevokeToken(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		method, path := "", ""
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, path = r.Method, r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		n := Connection{
+			AccessToken: "TEST",
+			testURL:     fmt.Sprintf("%s/devices", server.URL),
+		}
+
+		err := n.RevokeToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "DELETE"
+			if method != expected {
+				t.Fatalf("Expected method to equal %s, got %s", expected, method)
+			}
+		}
+
+		{
+			expected := "/oauth2/access_tokens/TEST"
+			if path != expected {
+				t.Fatalf("Expected path to equal %s, got %s", expected, path)
+			}
+		}
+	})
+
+	t.Run("Empty access token", func(t *testing.T) {
+		n := Connection{}
+
+		err := n.RevokeToken()
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Access token must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		state, err := n.Validate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := TokenValid
+			if state != expected {
+				t.Fatalf("Expected state to equal %s, got %s", expected, state)
+			}
+		}
+	})
+
+	t.Run("Revoked", func(t *testing.T) {
+		n, server := createTestErrorConnection(401, `{"error":"unauthorized","message":"unauthorized"}`)
+		defer server.Close()
+
+		state, err := n.Validate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := TokenRevoked
+			if state != expected {
+				t.Fatalf("Expected state to equal %s, got %s", expected, state)
+			}
+		}
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		n.TokenExpiry = time.Now().Add(-time.Hour)
+
+		state, err := n.Validate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := TokenExpired
+			if state != expected {
+				t.Fatalf("Expected state to equal %s, got %s", expected, state)
+			}
+		}
+	})
+
+	t.Run("Expired by Nest", func(t *testing.T) {
+		n, server := createTestErrorConnection(401, `{"error":"unauthorized","message":"authorization token expired"}`)
+		defer server.Close()
+
+		state, err := n.Validate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := TokenExpired
+			if state != expected {
+				t.Fatalf("Expected state to equal %s, got %s", expected, state)
+			}
+		}
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		n, server := createTestErrorConnection(403, `{"error":"forbidden","message":"missing product permission"}`)
+		defer server.Close()
+
+		state, err := n.Validate()
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := TokenUnknown
+			if state != expected {
+				t.Fatalf("Expected state to equal %s, got %s", expected, state)
+			}
+		}
+	})
+
+	t.Run("Forbidden token", func(t *testing.T) {
+		n, server := createTestErrorConnection(403, `{"error":"unauthorized","message":"access token revoked"}`)
+		defer server.Close()
+
+		state, err := n.Validate()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := TokenRevoked
+			if state != expected {
+				t.Fatalf("Expected state to equal %s, got %s", expected, state)
+			}
+		}
+	})
+
+	t.Run("Server error", func(t *testing.T) {
+		n, server := createTestErrorConnection(500, "Internal Server Error")
+		defer server.Close()
+
+		state, err := n.Validate()
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := TokenUnknown
+			if state != expected {
+				t.Fatalf("Expected state to equal %s, got %s", expected, state)
+			}
+		}
+	})
+}
@@ -0,0 +1,310 @@
+package nest
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache serves reads from recent responses instead of making a request. Everything read
+// is kept in a single copy of the data model, so a device fetched by GetAll or delivered
+// by a Stream also answers reads of its fields. Writes made through the connection
+// invalidate the device written to. A Cache is safe for concurrent use and can be shared
+// by connections using the same access token. The zero value is ready to use.
+type Cache struct {
+	// TTL is how long responses are used for, defaults to 30 seconds. It's used for any
+	// resource that doesn't have its own TTL.
+	TTL time.Duration
+
+	// ThermostatTTL is how long thermostat responses are used for
+	ThermostatTTL time.Duration
+
+	// SmokeCOAlarmTTL is how long smoke/co alarm responses are used for
+	SmokeCOAlarmTTL time.Duration
+
+	// CameraTTL is how long camera responses are used for
+	CameraTTL time.Duration
+
+	// StructureTTL is how long structure responses are used for
+	StructureTTL time.Duration
+
+	mu   sync.Mutex
+	data map[string]interface{}
+
+	// When each path in the data model was last fetched, which covers everything under it
+	fetched map[string]time.Time
+
+	// When each path was last written to
+	invalidated map[string]time.Time
+
+	// Number of streams connected and keeping the data up to date
+	live int
+}
+
+// Clear empties the cache
+func (c *Cache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.data = nil
+	c.fetched = nil
+	c.invalidated = nil
+}
+
+// ttl returns how long the response for path can be used. Reads of more than one type of
+// resource use the shortest TTL of them.
+func (c *Cache) ttl(path string) time.Duration {
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+
+	resources := []struct {
+		path string
+		ttl  time.Duration
+	}{
+		{"devices/thermostats", c.ThermostatTTL},
+		{"devices/smoke_co_alarms", c.SmokeCOAlarmTTL},
+		{"devices/cameras", c.CameraTTL},
+		{"structures", c.StructureTTL},
+	}
+
+	for _, r := range resources {
+		if r.ttl <= 0 {
+			continue
+		}
+
+		switch {
+		case hasPathPrefix(path, r.path):
+			return r.ttl
+		case hasPathPrefix(r.path, path) && r.ttl < ttl:
+			ttl = r.ttl
+		}
+	}
+
+	return ttl
+}
+
+// get returns the cached response for path as of now, and whether there was one
+func (c *Cache) get(path string, now time.Time) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The most recent fetch of path or anything it's under
+	var fetched time.Time
+	found := false
+
+	for p, t := range c.fetched {
+		if hasPathPrefix(path, p) && (!found || t.After(fetched)) {
+			fetched, found = t, true
+		}
+	}
+
+	if !found {
+		return nil, false
+	}
+
+	// Data kept up to date by a stream doesn't expire
+	if c.live == 0 && now.Sub(fetched) > c.ttl(path) {
+		return nil, false
+	}
+
+	for p, t := range c.invalidated {
+		if (hasPathPrefix(path, p) || hasPathPrefix(p, path)) && !fetched.After(t) {
+			return nil, false
+		}
+	}
+
+	val, ok := lookupPath(c.data, path)
+	if !ok {
+		// Nothing is at the path, which the API responds to with an empty body
+		return []byte{}, true
+	}
+
+	data, err := json.Marshal(val)
+	if err != nil {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// set stores data, a response or part of a stream, as the value at path. fetched is when
+// the request for it was started, so writes made while it was in flight aren't hidden.
+func (c *Cache) set(path string, data []byte, fetched time.Time) {
+	var val interface{}
+	if len(data) > 0 {
+		err := json.Unmarshal(data, &val)
+		if err != nil {
+			return
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.data == nil {
+		c.data = make(map[string]interface{})
+	}
+	if c.fetched == nil {
+		c.fetched = make(map[string]time.Time)
+	}
+
+	// Values fetched after this request started are newer, so it's dropped if one holds
+	// the whole path and otherwise they're put back once it's stored
+	newer := []string{}
+	for p, t := range c.fetched {
+		if !t.After(fetched) {
+			continue
+		}
+
+		if hasPathPrefix(path, p) {
+			return
+		}
+
+		if hasPathPrefix(p, path) {
+			newer = append(newer, p)
+		}
+	}
+
+	sort.Slice(newer, func(i, j int) bool {
+		return c.fetched[newer[i]].Before(c.fetched[newer[j]])
+	})
+
+	values := make([]interface{}, len(newer))
+	for i, p := range newer {
+		values[i], _ = lookupPath(c.data, p)
+	}
+
+	c.data = storePath(c.data, path, val)
+
+	for i, p := range newer {
+		c.data = storePath(c.data, p, values[i])
+	}
+
+	// The new value replaces everything fetched or written under it before the request
+	for p, t := range c.fetched {
+		if hasPathPrefix(p, path) && !t.After(fetched) {
+			delete(c.fetched, p)
+		}
+	}
+
+	for p, t := range c.invalidated {
+		if hasPathPrefix(p, path) && t.Before(fetched) {
+			delete(c.invalidated, p)
+		}
+	}
+
+	c.fetched[path] = fetched
+}
+
+// invalidate stops path, and everything under it, being served until it's fetched again
+func (c *Cache) invalidate(path string, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.invalidated == nil {
+		c.invalidated = make(map[string]time.Time)
+	}
+
+	c.invalidated[path] = now
+}
+
+// setLive records a stream connecting or disconnecting
+func (c *Cache) setLive(live bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if live {
+		c.live++
+	} else if c.live > 0 {
+		c.live--
+	}
+}
+
+// uncached returns a copy of the connection that reads from the API rather than the cache
+func (n *Connection) uncached() *Connection {
+	n2 := *n
+	n2.Cache = nil
+
+	return &n2
+}
+
+// cachePath returns where the response to url is in the data model, and false if url
+// isn't in the data model
+func (n *Connection) cachePath(url string) (string, bool) {
+	root := n.rootURL()
+	if url != root && !strings.HasPrefix(url, root+"/") {
+		return "", false
+	}
+
+	path := strings.Trim(strings.TrimPrefix(url, root), "/")
+
+	// Structures are requested under devices but are at the top of the data model
+	if hasPathPrefix(path, "devices/structures") {
+		path = strings.TrimPrefix(path, "devices/")
+	}
+
+	return path, true
+}
+
+// hasPathPrefix reports whether path is prefix or is under it
+func hasPathPrefix(path, prefix string) bool {
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// lookupPath returns the value at path in data
+func lookupPath(data map[string]interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return data, true
+	}
+
+	var val interface{} = data
+	for _, p := range strings.Split(path, "/") {
+		node, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		val, ok = node[p]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return val, true
+}
+
+// storePath sets the value at path in data, removing it if val is nil, and returns data
+func storePath(data map[string]interface{}, path string, val interface{}) map[string]interface{} {
+	if path == "" {
+		root, ok := val.(map[string]interface{})
+		if !ok {
+			root = make(map[string]interface{})
+		}
+		return root
+	}
+
+	parts := strings.Split(path, "/")
+
+	node := data
+	for _, p := range parts[:len(parts)-1] {
+		child, ok := node[p].(map[string]interface{})
+		if !ok {
+			child = make(map[string]interface{})
+			node[p] = child
+		}
+		node = child
+	}
+
+	last := parts[len(parts)-1]
+	if val == nil {
+		delete(node, last)
+	} else {
+		node[last] = val
+	}
+
+	return data
+}
@@ -0,0 +1,358 @@
+package nest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// createTestCacheConnection creates a connection with a cache to a server holding a
+// thermostat and a structure, which counts the reads made
+func createTestCacheConnection() (Connection, *httptest.Server, func() int) {
+	data := map[string]interface{}{
+		"devices": map[string]interface{}{
+			"thermostats": map[string]interface{}{
+				"abc": map[string]interface{}{"device_id": "abc", "name": "Hallway", "locale": "en-US", "label": "Hallway"},
+			},
+		},
+		"structures": map[string]interface{}{
+			"abc123": map[string]interface{}{"structure_id": "abc123", "name": "Home", "away": "home"},
+		},
+	}
+
+	reads := 0
+	mu := sync.Mutex{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		path := strings.Trim(r.URL.Path, "/")
+		if strings.HasPrefix(path, "devices/structures") {
+			path = strings.TrimPrefix(path, "devices/")
+		}
+
+		if r.Method == "PUT" {
+			body, _ := ioutil.ReadAll(r.Body)
+
+			val := map[string]interface{}{}
+			json.Unmarshal(body, &val)
+
+			device, _ := lookupPath(data, path)
+			for k, v := range val {
+				device.(map[string]interface{})[k] = v
+			}
+
+			w.Write(body)
+			return
+		}
+
+		reads++
+
+		val, ok := lookupPath(data, path)
+		if !ok {
+			w.Write(nil)
+			return
+		}
+
+		body, _ := json.Marshal(val)
+		w.Write(body)
+	}))
+
+	return Connection{
+		AccessToken: "TEST",
+		Cache:       &Cache{},
+		testURL:     fmt.Sprintf("%s/devices", server.URL),
+	}, server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return reads
+	}
+}
+
+func TestCache(t *testing.T) {
+	t.Run("Reads served from cache", func(t *testing.T) {
+		n, server, reads := createTestCacheConnection()
+		defer server.Close()
+
+		for i := 0; i < 3; i++ {
+			locale, err := n.GetThermostatLocale("abc")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			{
+				expected := "en-US"
+				if locale != expected {
+					t.Fatalf("Expected locale to equal %s, got %s", expected, locale)
+				}
+			}
+		}
+
+		{
+			expected := 1
+			if reads() != expected {
+				t.Fatalf("Expected %d read(s), got %d", expected, reads())
+			}
+		}
+	})
+
+	t.Run("Snapshot answers reads", func(t *testing.T) {
+		n, server, reads := createTestCacheConnection()
+		defer server.Close()
+
+		_, err := n.GetAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		name, err := n.GetThermostatName("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "Hallway"
+			if name != expected {
+				t.Fatalf("Expected name to equal %s, got %s", expected, name)
+			}
+		}
+
+		structure, err := n.GetStructure("abc123")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "Home"
+			if structure.Name != expected {
+				t.Fatalf("Expected structure name to equal %s, got %s", expected, structure.Name)
+			}
+		}
+
+		_, err = n.GetThermostatLocale("def")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := 1
+			if reads() != expected {
+				t.Fatalf("Expected %d read(s), got %d", expected, reads())
+			}
+		}
+	})
+
+	t.Run("Writes invalidate", func(t *testing.T) {
+		n, server, reads := createTestCacheConnection()
+		defer server.Close()
+
+		_, err := n.GetThermostat("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = n.SetThermostatLabel("abc", "Upstairs")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		label, err := n.GetThermostatLabel("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "Upstairs"
+			if label != expected {
+				t.Fatalf("Expected label to equal %s, got %s", expected, label)
+			}
+		}
+
+		{
+			expected := 2
+			if reads() != expected {
+				t.Fatalf("Expected %d read(s), got %d", expected, reads())
+			}
+		}
+
+		// Other resources are still cached
+		_, err = n.GetAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = n.GetStructure("abc123")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 3
+			if reads() != expected {
+				t.Fatalf("Expected %d read(s), got %d", expected, reads())
+			}
+		}
+	})
+
+	t.Run("Validate isn't cached", func(t *testing.T) {
+		n, server, reads := createTestCacheConnection()
+		defer server.Close()
+
+		for i := 0; i < 2; i++ {
+			_, err := n.Validate()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		{
+			expected := 2
+			if reads() != expected {
+				t.Fatalf("Expected %d read(s), got %d", expected, reads())
+			}
+		}
+	})
+}
+
+func TestCacheTTL(t *testing.T) {
+	now := time.Now()
+
+	c := &Cache{TTL: 10 * time.Second, ThermostatTTL: time.Minute}
+	c.set("", []byte(`{"devices":{"thermostats":{"abc":{"name":"Hallway"}}},"structures":{"abc123":{"name":"Home"}}}`), now)
+
+	tests := []struct {
+		path     string
+		after    time.Duration
+		expected bool
+	}{
+		{"devices/thermostats/abc/name", 30 * time.Second, true},
+		{"devices/thermostats/abc/name", 2 * time.Minute, false},
+		{"structures/abc123", 5 * time.Second, true},
+		{"structures/abc123", 30 * time.Second, false},
+		// Reads of everything use the shortest TTL
+		{"", 30 * time.Second, false},
+	}
+
+	for _, test := range tests {
+		if _, ok := c.get(test.path, now.Add(test.after)); ok != test.expected {
+			t.Fatalf("Expected %q cached after %s to equal %t, got %t", test.path, test.after, test.expected, ok)
+		}
+	}
+
+	t.Run("Live", func(t *testing.T) {
+		c.setLive(true)
+		defer c.setLive(false)
+
+		if _, ok := c.get("structures/abc123", now.Add(time.Hour)); !ok {
+			t.Fatal("Expected data kept up to date by a stream not to expire")
+		}
+	})
+}
+
+func TestCacheConcurrentWrites(t *testing.T) {
+	start := time.Now()
+	stale := []byte(`{"devices":{"thermostats":{"abc":{"label":"Hallway"},"def":{"label":"Kitchen"}}}}`)
+
+	t.Run("Write while reading", func(t *testing.T) {
+		c := &Cache{}
+
+		// A read of everything starts, then a write is made before it returns
+		c.invalidate("devices/thermostats/abc", start.Add(time.Second))
+		c.set("", stale, start)
+
+		if _, ok := c.get("devices/thermostats/abc/label", start.Add(2*time.Second)); ok {
+			t.Fatal("Expected the written thermostat not to be served from a read that started before the write")
+		}
+
+		if _, ok := c.get("devices/thermostats/def/label", start.Add(2*time.Second)); !ok {
+			t.Fatal("Expected other thermostats to be served")
+		}
+	})
+
+	t.Run("Newer read kept", func(t *testing.T) {
+		c := &Cache{}
+
+		c.set("devices/thermostats/abc", []byte(`{"label":"Upstairs"}`), start.Add(time.Second))
+		c.set("", stale, start)
+
+		data, ok := c.get("devices/thermostats/abc/label", start.Add(2*time.Second))
+		if !ok {
+			t.Fatal("Expected the label to be cached")
+		}
+
+		{
+			expected := `"Upstairs"`
+			if string(data) != expected {
+				t.Fatalf("Expected cached value to equal %s, got %s", expected, string(data))
+			}
+		}
+
+		// A response that's entirely older than what's cached is dropped
+		c.set("devices/thermostats/abc/label", []byte(`"Hallway"`), start)
+
+		data, _ = c.get("devices/thermostats/abc/label", start.Add(2*time.Second))
+
+		{
+			expected := `"Upstairs"`
+			if string(data) != expected {
+				t.Fatalf("Expected cached value to equal %s, got %s", expected, string(data))
+			}
+		}
+	})
+}
+
+func TestCacheStream(t *testing.T) {
+	streamMinBackoff = time.Millisecond
+	defer func() { streamMinBackoff = time.Second }()
+
+	n, server := createTestStreamConnection(t,
+		"event: put\ndata: {\"path\":\"/\",\"data\":{\"devices\":{\"thermostats\":{\"abc\":{\"device_id\":\"abc\",\"target_temperature_f\":68}}}}}\n\n",
+		"event: put\ndata: {\"path\":\"/devices/thermostats/abc/target_temperature_f\",\"data\":70}\n\n",
+		"event: auth_revoked\ndata: \"TEST\"\n\n",
+	)
+	defer server.Close()
+
+	n.Cache = &Cache{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s := n.Stream(ctx)
+
+	for s.Thermostats != nil || s.Errors != nil {
+		select {
+		case _, ok := <-s.Thermostats:
+			if !ok {
+				s.Thermostats = nil
+			}
+		case _, ok := <-s.Errors:
+			if !ok {
+				s.Errors = nil
+			}
+		case <-s.SmokeCOAlarms:
+		case <-s.Cameras:
+		case <-s.Structures:
+		}
+	}
+
+	data, ok := n.Cache.get("devices/thermostats/abc/target_temperature_f", time.Now())
+	if !ok {
+		t.Fatal("Expected the stream's data to be cached")
+	}
+
+	{
+		expected := "70"
+		if string(data) != expected {
+			t.Fatalf("Expected cached value to equal %s, got %s", expected, string(data))
+		}
+	}
+}
@@ -5,48 +5,131 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
-type cameraLastEvent struct {
-	HasSound         bool     `json:"has_sound"`
-	HasMotion        bool     `json:"has_motion"`
-	HasPerson        bool     `json:"has_person"`
-	StartTime        string   `json:"start_time"`
-	EndTime          string   `json:"end_time"`
-	UrlsExpireTime   string   `json:"urls_expire_time"`
-	WebURL           string   `json:"web_url"`
-	AppURL           string   `json:"app_url"`
-	ImageURL         string   `json:"image_url"`
-	AnimatedImageURL string   `json:"animated_image_url"`
-	ActivityZoneIDs  []string `json:"activity_zone_ids"`
-}
-
-type cameraActivityZone struct {
+// CameraEvent is an event that triggered a notification for a camera
+type CameraEvent struct {
+	HasSound         bool      `json:"has_sound"`
+	HasMotion        bool      `json:"has_motion"`
+	HasPerson        bool      `json:"has_person"`
+	StartTime        time.Time `json:"start_time"`
+	EndTime          time.Time `json:"end_time"`
+	UrlsExpireTime   time.Time `json:"urls_expire_time"`
+	WebURL           string    `json:"web_url"`
+	AppURL           string    `json:"app_url"`
+	ImageURL         string    `json:"image_url"`
+	AnimatedImageURL string    `json:"animated_image_url"`
+	ActivityZoneIDs  []string  `json:"activity_zone_ids"`
+}
+
+// UnmarshalJSON decodes a camera event, leaving timestamps that are empty as the zero time
+func (e *CameraEvent) UnmarshalJSON(data []byte) error {
+	type cameraEvent CameraEvent
+
+	aux := struct {
+		*cameraEvent
+		StartTime      timestamp `json:"start_time"`
+		EndTime        timestamp `json:"end_time"`
+		UrlsExpireTime timestamp `json:"urls_expire_time"`
+	}{
+		cameraEvent: (*cameraEvent)(e),
+	}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	e.StartTime = aux.StartTime.Time
+	e.EndTime = aux.EndTime.Time
+	e.UrlsExpireTime = aux.UrlsExpireTime.Time
+
+	return nil
+}
+
+// InProgress reports whether the event hasn't ended yet. Nest leaves the end time before
+// the start time while an event is in progress.
+func (e CameraEvent) InProgress() bool {
+	return e.EndTime.IsZero() || e.EndTime.Before(e.StartTime)
+}
+
+// ActivityZone is an area of a camera's view that events can be limited to
+type ActivityZone struct {
 	Name string `json:"name"`
 	ID   string `json:"id"`
 }
 
+// UnmarshalJSON decodes an activity zone, accepting an id that is a number or a string
+func (z *ActivityZone) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		Name string          `json:"name"`
+		ID   json.RawMessage `json:"id"`
+	}{}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	z.Name = aux.Name
+	z.ID = ""
+
+	// Strings are unquoted, numbers are kept as written
+	if len(aux.ID) > 0 && aux.ID[0] == '"' {
+		err = json.Unmarshal(aux.ID, &z.ID)
+		if err != nil {
+			return err
+		}
+	} else if string(aux.ID) != "null" {
+		z.ID = string(aux.ID)
+	}
+
+	return nil
+}
+
 // Camera contains all the data for an individual Nest camera
 type Camera struct {
-	DeviceID              string               `json:"device_id"`
-	SoftwareVersion       string               `json:"software_version"`
-	StructureID           string               `json:"structure_id"`
-	WhereID               string               `json:"where_id"`
-	WhereName             string               `json:"where_name"`
-	Name                  string               `json:"name"`
-	NameLong              string               `json:"name_long"`
-	IsOnline              bool                 `json:"is_online"`
-	IsStreaming           bool                 `json:"is_streaming"`
-	IsAudioInputEnabled   bool                 `json:"is_audio_input_enabled"`
-	LastIsOnlineChange    string               `json:"last_is_online_change"`
-	IsVideoHistoryEnabled bool                 `json:"is_video_history_enabled"`
-	WebURL                string               `json:"web_url"`
-	AppURL                string               `json:"app_url"`
-	IsPublicShareEnabled  bool                 `json:"is_public_share_enabled"`
-	ActivityZones         []cameraActivityZone `json:"activity_zones"`
-	PublicShareURL        string               `json:"public_share_url"`
-	SnapshotURL           string               `json:"snapshot_url"`
-	LastEvent             []cameraLastEvent    `json:"last_event"`
+	DeviceID              string         `json:"device_id"`
+	SoftwareVersion       string         `json:"software_version"`
+	StructureID           string         `json:"structure_id"`
+	WhereID               string         `json:"where_id"`
+	WhereName             string         `json:"where_name"`
+	Name                  string         `json:"name"`
+	NameLong              string         `json:"name_long"`
+	IsOnline              bool           `json:"is_online"`
+	IsStreaming           bool           `json:"is_streaming"`
+	IsAudioInputEnabled   bool           `json:"is_audio_input_enabled"`
+	LastIsOnlineChange    time.Time      `json:"last_is_online_change"`
+	IsVideoHistoryEnabled bool           `json:"is_video_history_enabled"`
+	WebURL                string         `json:"web_url"`
+	AppURL                string         `json:"app_url"`
+	IsPublicShareEnabled  bool           `json:"is_public_share_enabled"`
+	ActivityZones         []ActivityZone `json:"activity_zones"`
+	PublicShareURL        string         `json:"public_share_url"`
+	SnapshotURL           string         `json:"snapshot_url"`
+	LastEvent             *CameraEvent   `json:"last_event"`
+}
+
+// UnmarshalJSON decodes a camera, leaving timestamps that are empty as the zero time
+func (c *Camera) UnmarshalJSON(data []byte) error {
+	type camera Camera
+
+	aux := struct {
+		*camera
+		LastIsOnlineChange timestamp `json:"last_is_online_change"`
+	}{
+		camera: (*camera)(c),
+	}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	c.LastIsOnlineChange = aux.LastIsOnlineChange.Time
+
+	return nil
 }
 
 // GetCameras returns all Nest cameras along with all their data
@@ -123,61 +206,142 @@ func (n *Connection) GetCamera(deviceID string) (Camera, error) {
 
 // GetCameraSoftwareVersion returns the software version of the specified camera
 func (n *Connection) GetCameraSoftwareVersion(deviceID string) (string, error) {
-	return n.getValue("cameras", deviceID, "software_version")
+	var val string
+	err := n.getValue("cameras", deviceID, "software_version", &val)
+
+	return val, err
 }
 
 // GetCameraName returns the name of the specified camera
 func (n *Connection) GetCameraName(deviceID string) (string, error) {
-	return n.getValue("cameras", deviceID, "name")
+	var val string
+	err := n.getValue("cameras", deviceID, "name", &val)
+
+	return val, err
 }
 
 // IsCameraOnline returns true if the specified camera is online, false if it isn't
-func (n *Connection) IsCameraOnline(deviceID string) (string, error) {
-	return n.getValue("cameras", deviceID, "is_online")
+func (n *Connection) IsCameraOnline(deviceID string) (bool, error) {
+	var val bool
+	err := n.getValue("cameras", deviceID, "is_online", &val)
+
+	return val, err
 }
 
 // IsCameraStreaming returns true if the specified camera is actively streaming video, false if it isn't
-func (n *Connection) IsCameraStreaming(deviceID string) (string, error) {
-	return n.getValue("cameras", deviceID, "is_streaming")
+func (n *Connection) IsCameraStreaming(deviceID string) (bool, error) {
+	var val bool
+	err := n.getValue("cameras", deviceID, "is_streaming", &val)
+
+	return val, err
 }
 
 // IsCameraAudioInputEnabled returns true if the specified camera mic is listening, false if it isn't
-func (n *Connection) IsCameraAudioInputEnabled(deviceID string) (string, error) {
-	return n.getValue("cameras", deviceID, "is_audio_input_enabled")
+func (n *Connection) IsCameraAudioInputEnabled(deviceID string) (bool, error) {
+	var val bool
+	err := n.getValue("cameras", deviceID, "is_audio_input_enabled", &val)
+
+	return val, err
 }
 
 // IsCameraVideoHistoryEnabled returns true if the Nest Aware subscription is active, false if it isn't
-func (n *Connection) IsCameraVideoHistoryEnabled(deviceID string) (string, error) {
-	return n.getValue("cameras", deviceID, "is_video_history_enabled")
+func (n *Connection) IsCameraVideoHistoryEnabled(deviceID string) (bool, error) {
+	var val bool
+	err := n.getValue("cameras", deviceID, "is_video_history_enabled", &val)
+
+	return val, err
 }
 
 // GetCameraWebURL returns the web URL of the specified camera
 func (n *Connection) GetCameraWebURL(deviceID string) (string, error) {
-	return n.getValue("cameras", deviceID, "web_url")
+	var val string
+	err := n.getValue("cameras", deviceID, "web_url", &val)
+
+	return val, err
 }
 
 // GetCameraAppURL returns the app URL of the specified camera
 func (n *Connection) GetCameraAppURL(deviceID string) (string, error) {
-	return n.getValue("cameras", deviceID, "app_url")
+	var val string
+	err := n.getValue("cameras", deviceID, "app_url", &val)
+
+	return val, err
+}
+
+// IsCameraPublicShareEnabled returns true if the specified camera is shared publicly, false if it isn't
+func (n *Connection) IsCameraPublicShareEnabled(deviceID string) (bool, error) {
+	var val bool
+	err := n.getValue("cameras", deviceID, "is_public_share_enabled", &val)
+
+	return val, err
+}
+
+// GetCameraPublicShareURL returns the public share URL of the specified camera, which is
+// empty unless the camera is shared publicly
+func (n *Connection) GetCameraPublicShareURL(deviceID string) (string, error) {
+	var val string
+	err := n.getValue("cameras", deviceID, "public_share_url", &val)
+
+	return val, err
+}
+
+// GetCameraActivityZones returns the activity zones of the specified camera
+func (n *Connection) GetCameraActivityZones(deviceID string) ([]ActivityZone, error) {
+	val := []ActivityZone{}
+	err := n.getValue("cameras", deviceID, "activity_zones", &val)
+
+	return val, err
 }
 
 // GetCameraLastEvent returns info for the last event that triggered a notification for the specified camera
-func (n *Connection) GetCameraLastEvent(deviceID string) (string, error) {
-	return n.getValue("cameras", deviceID, "last_event")
+func (n *Connection) GetCameraLastEvent(deviceID string) (CameraEvent, error) {
+	var val CameraEvent
+	err := n.getValue("cameras", deviceID, "last_event", &val)
+
+	return val, err
 }
 
-// TurnOnStreaming turns on streaming for the specified camera
-func (n *Connection) TurnOnStreaming(deviceID string) error {
-	vals := make(map[string]interface{})
-	vals["is_streaming"] = true
+// EventActivityZones returns the camera's activity zones that event happened in. Zones
+// that the camera doesn't have any more are returned with only their id.
+func (c Camera) EventActivityZones(event CameraEvent) []ActivityZone {
+	zones := []ActivityZone{}
+
+	for _, id := range event.ActivityZoneIDs {
+		zone := ActivityZone{ID: id}
+
+		for _, z := range c.ActivityZones {
+			if z.ID == id {
+				zone = z
+				break
+			}
+		}
+
+		zones = append(zones, zone)
+	}
 
-	return n.setValue("cameras", deviceID, vals)
+	return zones
 }
 
-// TurnOffStreaming turns off streaming for the specified camera
-func (n *Connection) TurnOffStreaming(deviceID string) error {
-	vals := make(map[string]interface{})
-	vals["is_streaming"] = false
+// TurnOnStreaming turns on streaming for the specified camera and returns whether Nest
+// applied it
+func (n *Connection) TurnOnStreaming(deviceID string) (bool, error) {
+	return n.setStreaming(deviceID, true)
+}
+
+// TurnOffStreaming turns off streaming for the specified camera and returns whether Nest
+// left it streaming
+func (n *Connection) TurnOffStreaming(deviceID string) (bool, error) {
+	return n.setStreaming(deviceID, false)
+}
+
+// setStreaming writes whether the camera is streaming and returns the value Nest applied
+func (n *Connection) setStreaming(deviceID string, streaming bool) (bool, error) {
+	confirmed := cameraRequest{}
+
+	err := n.setValue("cameras", deviceID, cameraRequest{IsStreaming: streaming}, &confirmed)
+	if err != nil {
+		return false, err
+	}
 
-	return n.setValue("cameras", deviceID, vals)
+	return confirmed.IsStreaming, nil
 }
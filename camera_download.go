@@ -0,0 +1,133 @@
+package nest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// ErrEventExpired is returned when the image URLs of a camera event have expired and the
+// event is no longer the camera's last event, so the URLs can't be refreshed
+var ErrEventExpired = errors.New("Camera event image URLs have expired")
+
+// DownloadSnapshot writes a snapshot of the specified camera's current view to w and
+// returns its content type, e.g. image/jpeg. Snapshot URLs are short lived, so the camera
+// is fetched for a fresh one every time, bypassing the cache.
+func (n *Connection) DownloadSnapshot(deviceID string, w io.Writer) (string, error) {
+	camera, err := n.uncached().GetCamera(deviceID)
+	if err != nil {
+		return "", err
+	}
+
+	if camera.SnapshotURL == "" {
+		return "", fmt.Errorf("Camera %s doesn't have a snapshot URL", deviceID)
+	}
+
+	return n.download(camera.SnapshotURL, w)
+}
+
+// DownloadEventImage writes the image of a camera event to w and returns its content type.
+// If event is nil, or its URLs have expired, the camera's last event is fetched for fresh
+// URLs. ErrEventExpired is returned if event has since been replaced by a newer event.
+func (n *Connection) DownloadEventImage(deviceID string, event *CameraEvent, w io.Writer) (string, error) {
+	event, err := n.refreshEvent(deviceID, event)
+	if err != nil {
+		return "", err
+	}
+
+	if event.ImageURL == "" {
+		return "", fmt.Errorf("Camera %s event doesn't have an image URL", deviceID)
+	}
+
+	return n.download(event.ImageURL, w)
+}
+
+// DownloadEventAnimatedImage writes the animated image of a camera event to w like
+// DownloadEventImage
+func (n *Connection) DownloadEventAnimatedImage(deviceID string, event *CameraEvent, w io.Writer) (string, error) {
+	event, err := n.refreshEvent(deviceID, event)
+	if err != nil {
+		return "", err
+	}
+
+	if event.AnimatedImageURL == "" {
+		return "", fmt.Errorf("Camera %s event doesn't have an animated image URL", deviceID)
+	}
+
+	return n.download(event.AnimatedImageURL, w)
+}
+
+// refreshEvent returns event, or the camera's last event if event is nil or its URLs have
+// expired. The last event isn't read from the cache, which could hold the expired URLs.
+func (n *Connection) refreshEvent(deviceID string, event *CameraEvent) (*CameraEvent, error) {
+	if event != nil && !event.urlsExpired(time.Now()) {
+		return event, nil
+	}
+
+	last, err := n.uncached().GetCameraLastEvent(deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if event != nil && !last.StartTime.Equal(event.StartTime) {
+		return nil, ErrEventExpired
+	}
+
+	return &last, nil
+}
+
+// urlsExpired reports whether the event's URLs have expired at now
+func (e CameraEvent) urlsExpired(now time.Time) bool {
+	return !e.UrlsExpireTime.IsZero() && !now.Before(e.UrlsExpireTime)
+}
+
+// download writes the body of url to w and returns its content type. Image URLs are
+// signed, so the access token isn't sent with them.
+func (n *Connection) download(url string, w io.Writer) (string, error) {
+	req, err := n.newRequest(n.Context(), "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Del("Authorization")
+
+	resp, err := n.newClient().Do(req)
+	if err != nil {
+		// Report cancellation as the context's own error
+		if ctxErr := n.Context().Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+
+		return "", newAPIError(resp, data, url)
+	}
+
+	body := bufio.NewReader(resp.Body)
+
+	// Fall back to sniffing the image when the server doesn't say what it is
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		head, _ := body.Peek(512)
+		contentType = http.DetectContentType(head)
+	}
+
+	_, err = io.Copy(w, body)
+	if err != nil {
+		return "", err
+	}
+
+	return contentType, nil
+}
@@ -0,0 +1,186 @@
+package nest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// createTestDownloadConnection creates a connection to a server with a camera whose
+// snapshot and last event images can be downloaded
+func createTestDownloadConnection() (Connection, *httptest.Server, *string) {
+	authorization := "unset"
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/devices/cameras/abc":
+			w.Write([]byte(fmt.Sprintf(`{"device_id":"abc","snapshot_url":"%s/snapshot?auth=abc"}`, server.URL)))
+		case "/devices/cameras/def":
+			w.Write([]byte(`{"device_id":"def","snapshot_url":""}`))
+		case "/devices/cameras/abc/last_event":
+			w.Write([]byte(fmt.Sprintf(`{"start_time":"2016-12-29T00:00:00.000Z","image_url":"%s/image?auth=abc","animated_image_url":"%s/animated?auth=abc","urls_expire_time":"2099-01-01T00:00:00.000Z"}`, server.URL, server.URL)))
+		case "/snapshot", "/image":
+			authorization = r.Header.Get("Authorization")
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte("jpeg"))
+		case "/animated":
+			w.Header().Set("Content-Type", "image/gif")
+			w.Write([]byte("gif"))
+		default:
+			w.WriteHeader(404)
+			w.Write([]byte(`{"error":"not found","message":"not found"}`))
+		}
+	}))
+
+	return Connection{
+		AccessToken: "TEST",
+		testURL:     fmt.Sprintf("%s/devices", server.URL),
+	}, server, &authorization
+}
+
+func TestDownloadSnapshot(t *testing.T) {
+	n, server, authorization := createTestDownloadConnection()
+	defer server.Close()
+
+	t.Run("Success", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		contentType, err := n.DownloadSnapshot("abc", buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "image/jpeg"
+			if contentType != expected {
+				t.Fatalf("Expected content type to equal %s, got %s", expected, contentType)
+			}
+		}
+
+		{
+			expected := "jpeg"
+			if buf.String() != expected {
+				t.Fatalf("Expected image to equal %s, got %s", expected, buf.String())
+			}
+		}
+
+		// The access token isn't sent with signed image URLs
+		{
+			expected := ""
+			if *authorization != expected {
+				t.Fatalf("Expected Authorization to equal %s, got %s", expected, *authorization)
+			}
+		}
+	})
+
+	t.Run("Cache bypassed", func(t *testing.T) {
+		c := n
+		c.Cache = &Cache{}
+		c.Cache.set("devices/cameras/abc", []byte(fmt.Sprintf(`{"device_id":"abc","snapshot_url":"%s/expired"}`, server.URL)), time.Now())
+
+		_, err := c.DownloadSnapshot("abc", &bytes.Buffer{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("No snapshot URL", func(t *testing.T) {
+		_, err := n.DownloadSnapshot("def", &bytes.Buffer{})
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Camera def doesn't have a snapshot URL"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+}
+
+func TestDownloadEventImage(t *testing.T) {
+	n, server, _ := createTestDownloadConnection()
+	defer server.Close()
+
+	start := time.Date(2016, 12, 29, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Last event", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		_, err := n.DownloadEventImage("abc", nil, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "jpeg"
+			if buf.String() != expected {
+				t.Fatalf("Expected image to equal %s, got %s", expected, buf.String())
+			}
+		}
+	})
+
+	t.Run("Expired URLs refreshed", func(t *testing.T) {
+		event := &CameraEvent{
+			StartTime:        start,
+			AnimatedImageURL: fmt.Sprintf("%s/expired", server.URL),
+			UrlsExpireTime:   start.Add(time.Hour),
+		}
+
+		buf := &bytes.Buffer{}
+
+		contentType, err := n.DownloadEventAnimatedImage("abc", event, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "image/gif"
+			if contentType != expected {
+				t.Fatalf("Expected content type to equal %s, got %s", expected, contentType)
+			}
+		}
+	})
+
+	t.Run("Cache bypassed", func(t *testing.T) {
+		c := n
+		c.Cache = &Cache{}
+		c.Cache.set("devices/cameras/abc/last_event", []byte(fmt.Sprintf(`{"start_time":"2016-12-29T00:00:00.000Z","image_url":"%s/expired"}`, server.URL)), time.Now())
+
+		_, err := c.DownloadEventImage("abc", nil, &bytes.Buffer{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Expired event replaced", func(t *testing.T) {
+		event := &CameraEvent{
+			StartTime:      start.Add(-time.Hour),
+			ImageURL:       fmt.Sprintf("%s/expired", server.URL),
+			UrlsExpireTime: start,
+		}
+
+		_, err := n.DownloadEventImage("abc", event, &bytes.Buffer{})
+		if !errors.Is(err, ErrEventExpired) {
+			t.Fatalf("Expected errors.Is(%v), got %v", ErrEventExpired, err)
+		}
+	})
+
+	t.Run("Image not found", func(t *testing.T) {
+		event := &CameraEvent{
+			StartTime: start,
+			ImageURL:  fmt.Sprintf("%s/missing", server.URL),
+		}
+
+		_, err := n.DownloadEventImage("abc", event, &bytes.Buffer{})
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Expected errors.Is(%v), got %v", ErrNotFound, err)
+		}
+	})
+}
@@ -0,0 +1,179 @@
+package nest
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// CameraEventStore stores the history of camera events. Events are identified by their
+// camera and start time, so adding an event that is already stored replaces it with its
+// latest state, e.g. once it has ended.
+type CameraEventStore interface {
+	Add(deviceID string, event CameraEvent) error
+	Events(deviceID string) ([]CameraEvent, error)
+}
+
+// MemoryEventStore is a CameraEventStore that keeps events in memory. The zero value is
+// ready to use.
+type MemoryEventStore struct {
+	// MaxEvents is the number of events kept per camera, the oldest events are dropped
+	// once it's reached. Every event is kept if zero.
+	MaxEvents int
+
+	mu     sync.Mutex
+	events map[string][]CameraEvent
+}
+
+// Add stores event, replacing a stored event of the camera with the same start time
+func (s *MemoryEventStore) Add(deviceID string, event CameraEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.events == nil {
+		s.events = make(map[string][]CameraEvent)
+	}
+
+	events := s.events[deviceID]
+
+	for i, e := range events {
+		if e.StartTime.Equal(event.StartTime) {
+			events[i] = event
+			return nil
+		}
+	}
+
+	events = append(events, event)
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].StartTime.Before(events[j].StartTime)
+	})
+
+	if s.MaxEvents > 0 && len(events) > s.MaxEvents {
+		events = events[len(events)-s.MaxEvents:]
+	}
+
+	s.events[deviceID] = events
+
+	return nil
+}
+
+// Events returns the stored events of the camera, oldest first
+func (s *MemoryEventStore) Events(deviceID string) ([]CameraEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := make([]CameraEvent, len(s.events[deviceID]))
+	copy(events, s.events[deviceID])
+
+	return events, nil
+}
+
+// CameraEventWatcher records the last event of each camera it observes into a store,
+// building up a history of events that the API itself doesn't keep. The zero value records
+// into a new MemoryEventStore.
+type CameraEventWatcher struct {
+	// Store receives every new or changed event
+	Store CameraEventStore
+
+	// OnEvent is called with every new or changed event after it's stored, if set
+	OnEvent func(camera Camera, event CameraEvent)
+
+	mu   sync.Mutex
+	last map[string]CameraEvent
+}
+
+// NewCameraEventWatcher returns a watcher that records events into store, or into a new
+// MemoryEventStore if store is nil
+func NewCameraEventWatcher(store CameraEventStore) *CameraEventWatcher {
+	return &CameraEventWatcher{Store: store}
+}
+
+// Observe records the camera's last event if it's different from the last one observed for
+// the camera. Only the event's start and end, what was detected and its activity zones are
+// compared, as Nest changes its URLs while the event stays the same. Cameras can come from
+// GetCameras, GetCamera or a Stream.
+func (w *CameraEventWatcher) Observe(camera Camera) error {
+	if camera.LastEvent == nil {
+		return nil
+	}
+
+	event := *camera.LastEvent
+
+	w.mu.Lock()
+	if w.last == nil {
+		w.last = make(map[string]CameraEvent)
+	}
+	if w.Store == nil {
+		w.Store = &MemoryEventStore{}
+	}
+
+	last, ok := w.last[camera.DeviceID]
+	if ok && last.sameAs(event) {
+		w.mu.Unlock()
+		return nil
+	}
+	store := w.Store
+	w.mu.Unlock()
+
+	// The event is only marked as seen once it's stored, so it's tried again next time
+	err := store.Add(camera.DeviceID, event)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	w.last[camera.DeviceID] = event
+	w.mu.Unlock()
+
+	if w.OnEvent != nil {
+		w.OnEvent(camera, event)
+	}
+
+	return nil
+}
+
+// Watch observes every camera received on cameras until the channel is closed or ctx is
+// cancelled. Pass the Cameras channel of a StreamFanout subscription rather than a
+// Stream's, which would block on the stream's other channels. It stops at the first error
+// from the store.
+func (w *CameraEventWatcher) Watch(ctx context.Context, cameras <-chan Camera) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case camera, ok := <-cameras:
+			if !ok {
+				return nil
+			}
+
+			err := w.Observe(camera)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// sameAs reports whether e and other are the same event in the same state, ignoring their
+// URLs
+func (e CameraEvent) sameAs(other CameraEvent) bool {
+	if !e.StartTime.Equal(other.StartTime) || !e.EndTime.Equal(other.EndTime) {
+		return false
+	}
+
+	if e.HasSound != other.HasSound || e.HasMotion != other.HasMotion || e.HasPerson != other.HasPerson {
+		return false
+	}
+
+	if len(e.ActivityZoneIDs) != len(other.ActivityZoneIDs) {
+		return false
+	}
+
+	for i, id := range e.ActivityZoneIDs {
+		if other.ActivityZoneIDs[i] != id {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,212 @@
+package nest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// failingEventStore is a MemoryEventStore that fails the given number of adds
+type failingEventStore struct {
+	MemoryEventStore
+	failures int
+}
+
+func (s *failingEventStore) Add(deviceID string, event CameraEvent) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("Store unavailable")
+	}
+
+	return s.MemoryEventStore.Add(deviceID, event)
+}
+
+func createTestCameraEvent(start time.Time, ended bool) *CameraEvent {
+	event := &CameraEvent{
+		HasMotion:       true,
+		StartTime:       start,
+		ActivityZoneIDs: []string{"1"},
+	}
+
+	if ended {
+		event.EndTime = start.Add(time.Minute)
+	}
+
+	return event
+}
+
+func TestEventActivityZones(t *testing.T) {
+	camera := Camera{}
+
+	err := json.Unmarshal([]byte(`{"device_id":"abc","activity_zones":[{"name":"Driveway","id":1},{"name":"Porch","id":"2"}],"last_event":{"has_person":true,"activity_zone_ids":["2","3"]}}`), &camera)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		expected := []ActivityZone{{Name: "Porch", ID: "2"}, {ID: "3"}}
+		zones := camera.EventActivityZones(*camera.LastEvent)
+		if !reflect.DeepEqual(zones, expected) {
+			t.Fatalf("Expected zones to equal %v, got %v", expected, zones)
+		}
+	}
+
+	{
+		expected := "1"
+		if camera.ActivityZones[0].ID != expected {
+			t.Fatalf("Expected ID to equal %s, got %s", expected, camera.ActivityZones[0].ID)
+		}
+	}
+}
+
+func TestCameraEventWatcher(t *testing.T) {
+	start := time.Date(2016, 12, 29, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Distinct events recorded", func(t *testing.T) {
+		store := &MemoryEventStore{}
+		w := NewCameraEventWatcher(store)
+
+		observed := 0
+		w.OnEvent = func(camera Camera, event CameraEvent) {
+			observed++
+		}
+
+		cameras := []Camera{
+			{DeviceID: "abc", LastEvent: createTestCameraEvent(start, false)},
+			{DeviceID: "abc", LastEvent: createTestCameraEvent(start, false)},
+			{DeviceID: "abc", LastEvent: createTestCameraEvent(start, true)},
+			{DeviceID: "abc", LastEvent: createTestCameraEvent(start.Add(time.Hour), true)},
+			{DeviceID: "abc"},
+		}
+
+		for _, camera := range cameras {
+			err := w.Observe(camera)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		events, err := store.Events("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 2
+			if len(events) != expected {
+				t.Fatalf("Expected %d event(s), got %d", expected, len(events))
+			}
+		}
+
+		// The first event was replaced once it ended
+		if events[0].InProgress() {
+			t.Fatal("Expected the first event to have ended")
+		}
+
+		{
+			expected := 3
+			if observed != expected {
+				t.Fatalf("Expected OnEvent to be called %d time(s), got %d", expected, observed)
+			}
+		}
+	})
+
+	t.Run("URL changes ignored", func(t *testing.T) {
+		w := &CameraEventWatcher{}
+
+		observed := 0
+		w.OnEvent = func(camera Camera, event CameraEvent) {
+			observed++
+		}
+
+		for _, url := range []string{"https://video.nest.com/a", "https://video.nest.com/b"} {
+			event := createTestCameraEvent(start, false)
+			event.WebURL, event.ImageURL = url, url+".jpg"
+
+			err := w.Observe(Camera{DeviceID: "abc", LastEvent: event})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		{
+			expected := 1
+			if observed != expected {
+				t.Fatalf("Expected OnEvent to be called %d time(s), got %d", expected, observed)
+			}
+		}
+	})
+
+	t.Run("Store errors retried", func(t *testing.T) {
+		store := &failingEventStore{failures: 1}
+		w := NewCameraEventWatcher(store)
+
+		camera := Camera{DeviceID: "abc", LastEvent: createTestCameraEvent(start, true)}
+
+		err := w.Observe(camera)
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		err = w.Observe(camera)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		events, _ := store.Events("abc")
+
+		{
+			expected := 1
+			if len(events) != expected {
+				t.Fatalf("Expected %d event(s), got %d", expected, len(events))
+			}
+		}
+	})
+
+	t.Run("Max events", func(t *testing.T) {
+		store := &MemoryEventStore{MaxEvents: 2}
+		w := NewCameraEventWatcher(store)
+
+		for i := 0; i < 3; i++ {
+			err := w.Observe(Camera{DeviceID: "abc", LastEvent: createTestCameraEvent(start.Add(time.Duration(i)*time.Hour), true)})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		events, _ := store.Events("abc")
+
+		{
+			expected := start.Add(time.Hour)
+			if len(events) != 2 || !events[0].StartTime.Equal(expected) {
+				t.Fatalf("Expected the 2 newest events, got %v", events)
+			}
+		}
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		w := &CameraEventWatcher{}
+
+		cameras := make(chan Camera, 2)
+		cameras <- Camera{DeviceID: "abc", LastEvent: createTestCameraEvent(start, true)}
+		cameras <- Camera{DeviceID: "def", LastEvent: createTestCameraEvent(start, true)}
+		close(cameras)
+
+		err := w.Watch(context.Background(), cameras)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		events, _ := w.Store.Events("def")
+
+		{
+			expected := 1
+			if len(events) != expected {
+				t.Fatalf("Expected %d event(s), got %d", expected, len(events))
+			}
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package nest
+
+import (
+	"context"
+)
+
+// CameraPolicy says whether the cameras in a structure should be streaming while the
+// structure is home and while it's away. A common policy is to only stream while away:
+//
+//	CameraPolicy{StreamWhenAway: true}
+type CameraPolicy struct {
+	StreamWhenHome bool
+	StreamWhenAway bool
+}
+
+// streaming returns whether cameras should be streaming in the away state, and false if
+// the state isn't known
+func (p CameraPolicy) streaming(away AwayState) (bool, bool) {
+	switch away {
+	case AwayStateHome:
+		return p.StreamWhenHome, true
+	case AwayStateAway:
+		return p.StreamWhenAway, true
+	}
+
+	return false, false
+}
+
+// ApplyCameraPolicy turns streaming on or off for each camera in the structure to match
+// the policy for the structure's Away state, and returns the ids of the cameras that were
+// changed. Cameras that are offline or already match the policy are left alone, and
+// nothing is changed if the Away state isn't known.
+func (n *Connection) ApplyCameraPolicy(structure Structure, policy CameraPolicy) ([]string, error) {
+	changed := []string{}
+
+	streaming, ok := policy.streaming(structure.Away)
+	if !ok {
+		return changed, nil
+	}
+
+	for _, deviceID := range structure.Cameras {
+		camera, err := n.GetCamera(deviceID)
+		if err != nil {
+			return changed, err
+		}
+
+		if !camera.IsOnline || camera.IsStreaming == streaming {
+			continue
+		}
+
+		if streaming {
+			_, err = n.TurnOnStreaming(deviceID)
+		} else {
+			_, err = n.TurnOffStreaming(deviceID)
+		}
+		if err != nil {
+			return changed, err
+		}
+
+		changed = append(changed, deviceID)
+	}
+
+	return changed, nil
+}
+
+// WatchCameraPolicy applies the policy to every structure received on structures, such as
+// a StreamFanout subscription's Structures channel, until the channel is closed or ctx is
+// cancelled. The policy is only applied when a structure's Away state changes, or the
+// first time the structure is received. It stops at the first error.
+func (n *Connection) WatchCameraPolicy(ctx context.Context, structures <-chan Structure, policy CameraPolicy) error {
+	last := make(map[string]AwayState)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case structure, ok := <-structures:
+			if !ok {
+				return nil
+			}
+
+			away, seen := last[structure.StructureID]
+			if seen && away == structure.Away {
+				continue
+			}
+			last[structure.StructureID] = structure.Away
+
+			_, err := n.WithContext(ctx).ApplyCameraPolicy(structure, policy)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package nest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// createTestPolicyConnection creates a connection to a server holding the given cameras,
+// which can have streaming turned on and off
+func createTestPolicyConnection(cameras map[string]*Camera) (Connection, *httptest.Server, *[]string) {
+	writes := []string{}
+	mu := sync.Mutex{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		camera, ok := cameras[strings.TrimPrefix(r.URL.Path, "/devices/cameras/")]
+		if !ok {
+			w.Write(nil)
+			return
+		}
+
+		if r.Method == "PUT" {
+			data, _ := ioutil.ReadAll(r.Body)
+			json.Unmarshal(data, camera)
+			writes = append(writes, camera.DeviceID)
+			w.Write(data)
+			return
+		}
+
+		data, _ := json.Marshal(camera)
+		w.Write(data)
+	}))
+
+	return Connection{
+		AccessToken: "TEST",
+		testURL:     fmt.Sprintf("%s/devices", server.URL),
+	}, server, &writes
+}
+
+func TestApplyCameraPolicy(t *testing.T) {
+	policy := CameraPolicy{StreamWhenAway: true}
+
+	t.Run("Away", func(t *testing.T) {
+		n, server, _ := createTestPolicyConnection(map[string]*Camera{
+			"abc": {DeviceID: "abc", IsOnline: true},
+			"def": {DeviceID: "def", IsOnline: true, IsStreaming: true},
+			"ghi": {DeviceID: "ghi", IsOnline: false},
+		})
+		defer server.Close()
+
+		changed, err := n.ApplyCameraPolicy(Structure{Away: AwayStateAway, Cameras: []string{"abc", "def", "ghi"}}, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := []string{"abc"}
+			if !reflect.DeepEqual(changed, expected) {
+				t.Fatalf("Expected changed cameras to equal %v, got %v", expected, changed)
+			}
+		}
+	})
+
+	t.Run("Home", func(t *testing.T) {
+		cameras := map[string]*Camera{
+			"abc": {DeviceID: "abc", IsOnline: true, IsStreaming: true},
+		}
+
+		n, server, _ := createTestPolicyConnection(cameras)
+		defer server.Close()
+
+		_, err := n.ApplyCameraPolicy(Structure{Away: AwayStateHome, Cameras: []string{"abc"}}, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if cameras["abc"].IsStreaming {
+			t.Fatal("Expected streaming to be turned off")
+		}
+	})
+
+	t.Run("Unknown away state", func(t *testing.T) {
+		n, server, writes := createTestPolicyConnection(map[string]*Camera{
+			"abc": {DeviceID: "abc", IsOnline: true},
+		})
+		defer server.Close()
+
+		_, err := n.ApplyCameraPolicy(Structure{Away: AwayStateUnknown, Cameras: []string{"abc"}}, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 0
+			if len(*writes) != expected {
+				t.Fatalf("Expected %d write(s), got %d", expected, len(*writes))
+			}
+		}
+	})
+
+	t.Run("Camera not found", func(t *testing.T) {
+		n, server, _ := createTestPolicyConnection(map[string]*Camera{})
+		defer server.Close()
+
+		_, err := n.ApplyCameraPolicy(Structure{Away: AwayStateAway, Cameras: []string{"abc"}}, policy)
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+	})
+}
+
+func TestWatchCameraPolicy(t *testing.T) {
+	n, server, writes := createTestPolicyConnection(map[string]*Camera{
+		"abc": {DeviceID: "abc", IsOnline: true},
+	})
+	defer server.Close()
+
+	structures := make(chan Structure, 4)
+	structures <- Structure{StructureID: "abc123", Away: AwayStateAway, Cameras: []string{"abc"}}
+	structures <- Structure{StructureID: "abc123", Away: AwayStateAway, Cameras: []string{"abc"}}
+	structures <- Structure{StructureID: "abc123", Away: AwayStateHome, Cameras: []string{"abc"}}
+	structures <- Structure{StructureID: "abc123", Away: AwayStateHome, Cameras: []string{"abc"}}
+	close(structures)
+
+	err := n.WatchCameraPolicy(context.Background(), structures, CameraPolicy{StreamWhenAway: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		expected := 2
+		if len(*writes) != expected {
+			t.Fatalf("Expected %d write(s), got %d", expected, len(*writes))
+		}
+	}
+}
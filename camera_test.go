@@ -1,6 +1,10 @@
 package nest
 
-import "testing"
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
 
 func TestGetCameras(t *testing.T) {
 	t.Run("One camera found", func(t *testing.T) {
@@ -257,9 +261,9 @@ func TestIsCameraOnline(t *testing.T) {
 		}
 
 		{
-			expected := "true"
+			expected := true
 			if isOnline != expected {
-				t.Fatalf("Expected Is Online to equal %s, got %s", expected, isOnline)
+				t.Fatalf("Expected Is Online to equal %t, got %t", expected, isOnline)
 			}
 		}
 	})
@@ -310,9 +314,9 @@ func TestIsCameraStreaming(t *testing.T) {
 		}
 
 		{
-			expected := "true"
+			expected := true
 			if isStreaming != expected {
-				t.Fatalf("Expected Is Streaming to equal %s, got %s", expected, isStreaming)
+				t.Fatalf("Expected Is Streaming to equal %t, got %t", expected, isStreaming)
 			}
 		}
 	})
@@ -363,9 +367,9 @@ func TestIsCameraAudioInputEnabled(t *testing.T) {
 		}
 
 		{
-			expected := "true"
+			expected := true
 			if isAudioEnabled != expected {
-				t.Fatalf("Expected Is Audio Input Enabled to equal %s, got %s", expected, isAudioEnabled)
+				t.Fatalf("Expected Is Audio Input Enabled to equal %t, got %t", expected, isAudioEnabled)
 			}
 		}
 	})
@@ -416,9 +420,9 @@ func TestIsCameraVideoHistoryEnabled(t *testing.T) {
 		}
 
 		{
-			expected := "true"
+			expected := true
 			if isVideoHistoryEnabled != expected {
-				t.Fatalf("Expected Is Video History Enabled to equal %s, got %s", expected, isVideoHistoryEnabled)
+				t.Fatalf("Expected Is Video History Enabled to equal %t, got %t", expected, isVideoHistoryEnabled)
 			}
 		}
 	})
@@ -564,6 +568,165 @@ func TestGetCameraAppURL(t *testing.T) {
 	})
 }
 
+func TestIsCameraPublicShareEnabled(t *testing.T) {
+	t.Run("Camera found", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		isPublicShareEnabled, err := n.IsCameraPublicShareEnabled("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := true
+			if isPublicShareEnabled != expected {
+				t.Fatalf("Expected Is Public Share Enabled to equal %t, got %t", expected, isPublicShareEnabled)
+			}
+		}
+	})
+
+	t.Run("Invalid device id", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		_, err := n.IsCameraPublicShareEnabled("")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Device ID must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Camera not found", func(t *testing.T) {
+		n, server := createTestConnection(2)
+		defer server.Close()
+
+		_, err := n.IsCameraPublicShareEnabled("def")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Camera is_public_share_enabled not found"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+}
+
+func TestGetCameraPublicShareURL(t *testing.T) {
+	t.Run("Camera found", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		publicShareURL, err := n.GetCameraPublicShareURL("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "https://video.nest.com/live/abc"
+			if publicShareURL != expected {
+				t.Fatalf("Expected Public Share URL to equal %s, got %s", expected, publicShareURL)
+			}
+		}
+	})
+
+	t.Run("Invalid device id", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		_, err := n.GetCameraPublicShareURL("")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Device ID must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Camera not found", func(t *testing.T) {
+		n, server := createTestConnection(2)
+		defer server.Close()
+
+		_, err := n.GetCameraPublicShareURL("def")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Camera public_share_url not found"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+}
+
+func TestGetCameraActivityZones(t *testing.T) {
+	t.Run("Camera found", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		activityZones, err := n.GetCameraActivityZones("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := []ActivityZone{{Name: "Driveway", ID: "1"}}
+			if !reflect.DeepEqual(activityZones, expected) {
+				t.Fatalf("Expected Activity Zones to equal %v, got %v", expected, activityZones)
+			}
+		}
+	})
+
+	t.Run("Invalid device id", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		_, err := n.GetCameraActivityZones("")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Device ID must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Camera not found", func(t *testing.T) {
+		n, server := createTestConnection(2)
+		defer server.Close()
+
+		_, err := n.GetCameraActivityZones("def")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Camera activity_zones not found"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+}
+
 func TestGetCameraLastEvent(t *testing.T) {
 	t.Run("Camera found", func(t *testing.T) {
 		n, server := createTestConnection(1)
@@ -575,9 +738,16 @@ func TestGetCameraLastEvent(t *testing.T) {
 		}
 
 		{
-			expected := "event"
-			if lastEvent != expected {
-				t.Fatalf("Expected Web URL to equal %s, got %s", expected, lastEvent)
+			expected := true
+			if lastEvent.HasMotion != expected {
+				t.Fatalf("Expected Has Motion to equal %t, got %t", expected, lastEvent.HasMotion)
+			}
+		}
+
+		{
+			expected := parseTestTime("2016-12-29T00:00:00.000Z")
+			if !lastEvent.StartTime.Equal(expected) {
+				t.Fatalf("Expected Start Time to equal %s, got %s", expected, lastEvent.StartTime)
 			}
 		}
 	})
@@ -622,14 +792,14 @@ func TestTurnOnStreaming(t *testing.T) {
 	defer server.Close()
 
 	t.Run("Success", func(t *testing.T) {
-		err := n.TurnOnStreaming("abc")
+		_, err := n.TurnOnStreaming("abc")
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("Invalid device id", func(t *testing.T) {
-		err := n.TurnOnStreaming("")
+		_, err := n.TurnOnStreaming("")
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -648,14 +818,14 @@ func TestTurnOffStreaming(t *testing.T) {
 	defer server.Close()
 
 	t.Run("Success", func(t *testing.T) {
-		err := n.TurnOffStreaming("abc")
+		_, err := n.TurnOffStreaming("abc")
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("Invalid device id", func(t *testing.T) {
-		err := n.TurnOffStreaming("")
+		_, err := n.TurnOffStreaming("")
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -668,3 +838,28 @@ func TestTurnOffStreaming(t *testing.T) {
 		}
 	})
 }
+
+func TestActivityZoneUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{`{"name":"Driveway","id":1}`, "1"},
+		{`{"name":"Driveway","id":"2"}`, "2"},
+		{`{"name":"Driveway","id":"abc"}`, "abc"},
+		{`{"name":"Driveway"}`, ""},
+	}
+
+	for _, test := range tests {
+		zone := ActivityZone{}
+
+		err := json.Unmarshal([]byte(test.data), &zone)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if zone.ID != test.expected {
+			t.Fatalf("Expected ID of %s to equal %s, got %s", test.data, test.expected, zone.ID)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package nest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Hosts that Nest redirects API requests to, which are sent the access token
+var trustedHostSuffixes = []string{".nest.com", ".firebaseio.com"}
+
+// newClient creates a client from the connection's settings. The client is a shallow
+// copy of HTTPClient when it's set, so requests share its transport and connection pool.
+func (n *Connection) newClient() *http.Client {
+	client := &http.Client{}
+	if n.HTTPClient != nil {
+		c := *n.HTTPClient
+		client = &c
+	}
+
+	if n.Timeout > 0 {
+		client.Timeout = n.Timeout
+	}
+
+	// Need a custom redirect policy because the default http client
+	// doesn't forward the Authorization header when a redirect 3xx is
+	// received to a different host, which Nest always does
+	checkRedirect := client.CheckRedirect
+	client.CheckRedirect = func(redirRequest *http.Request, via []*http.Request) error {
+		maxRedirects := n.MaxRedirects
+		if maxRedirects <= 0 {
+			maxRedirects = 10
+		}
+
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+
+		if isTrustedHost(redirRequest.URL.Host, via[0].URL.Host) {
+			redirRequest.Header = via[0].Header.Clone()
+		} else {
+			redirRequest.Header.Del("Authorization")
+		}
+
+		if checkRedirect != nil {
+			return checkRedirect(redirRequest, via)
+		}
+		return nil
+	}
+
+	return client
+}
+
+// newRequest creates an authorized request
+func (n *Connection) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", n.AccessToken))
+
+	if n.UserAgent != "" {
+		req.Header.Set("User-Agent", n.UserAgent)
+	}
+
+	return req, nil
+}
+
+// isTrustedHost returns true if a redirect to host may be sent the access token
+func isTrustedHost(host, originalHost string) bool {
+	if host == originalHost {
+		return true
+	}
+
+	hostname := strings.ToLower(host)
+	if i := strings.LastIndex(hostname, ":"); i != -1 {
+		hostname = hostname[:i]
+	}
+
+	for _, suffix := range trustedHostSuffixes {
+		if strings.HasSuffix(hostname, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,197 @@
+package nest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type countingTransport struct {
+	requests int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests++
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestHTTPClient(t *testing.T) {
+	t.Run("Custom client used", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		transport := &countingTransport{}
+		n.HTTPClient = &http.Client{Transport: transport}
+
+		_, err := n.GetThermostats()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = n.GetCameras()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 2
+			if transport.requests != expected {
+				t.Fatalf("Expected %d request(s) through the transport, got %d", expected, transport.requests)
+			}
+		}
+	})
+
+	t.Run("User agent", func(t *testing.T) {
+		userAgent := ""
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userAgent = r.Header.Get("User-Agent")
+			w.Write([]byte("{}"))
+		}))
+		defer server.Close()
+
+		n := Connection{
+			AccessToken: "TEST",
+			UserAgent:   "nest-test/1.0",
+			testURL:     fmt.Sprintf("%s/devices", server.URL),
+		}
+
+		_, err := n.GetThermostats()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "nest-test/1.0"
+			if userAgent != expected {
+				t.Fatalf("Expected User-Agent to equal %s, got %s", expected, userAgent)
+			}
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		done := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-done
+		}))
+		defer server.Close()
+		defer close(done)
+
+		n := Connection{
+			AccessToken: "TEST",
+			Timeout:     10 * time.Millisecond,
+			testURL:     fmt.Sprintf("%s/devices", server.URL),
+		}
+
+		_, err := n.GetThermostats()
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+	})
+}
+
+func TestRedirects(t *testing.T) {
+	t.Run("Authorization forwarded to same host", func(t *testing.T) {
+		authorization := ""
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/redirected/thermostats" {
+				http.Redirect(w, r, "/redirected/thermostats", http.StatusTemporaryRedirect)
+				return
+			}
+
+			authorization = r.Header.Get("Authorization")
+			w.Write([]byte("{}"))
+		}))
+		defer server.Close()
+
+		n := Connection{
+			AccessToken: "TEST",
+			testURL:     fmt.Sprintf("%s/devices", server.URL),
+		}
+
+		_, err := n.GetThermostats()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "Bearer TEST"
+			if authorization != expected {
+				t.Fatalf("Expected Authorization to equal %s, got %s", expected, authorization)
+			}
+		}
+	})
+
+	t.Run("Authorization not forwarded to other hosts", func(t *testing.T) {
+		authorization := "unset"
+
+		other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			w.Write([]byte("{}"))
+		}))
+		defer other.Close()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, other.URL, http.StatusTemporaryRedirect)
+		}))
+		defer server.Close()
+
+		n := Connection{
+			AccessToken: "TEST",
+			testURL:     fmt.Sprintf("%s/devices", server.URL),
+		}
+
+		_, err := n.GetThermostats()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := ""
+			if authorization != expected {
+				t.Fatalf("Expected Authorization to equal %s, got %s", expected, authorization)
+			}
+		}
+	})
+
+	t.Run("Redirect limit", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, r.URL.String(), http.StatusTemporaryRedirect)
+		}))
+		defer server.Close()
+
+		n := Connection{
+			AccessToken:  "TEST",
+			MaxRedirects: 3,
+			testURL:      fmt.Sprintf("%s/devices", server.URL),
+		}
+
+		_, err := n.GetThermostats()
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+	})
+}
+
+func TestIsTrustedHost(t *testing.T) {
+	tests := []struct {
+		host     string
+		expected bool
+	}{
+		{"developer-api.nest.com", true},
+		{"firebase-apiserver03-tah01-iad01.dapi.production.nest.com:9553", true},
+		{"example.firebaseio.com", true},
+		{"example.com", false},
+		{"nest.com.example.com", false},
+	}
+
+	for _, test := range tests {
+		if isTrustedHost(test.host, "developer-api.nest.com") != test.expected {
+			t.Fatalf("Expected isTrustedHost(%s) to equal %t", test.host, test.expected)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package nest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ErrNotConfirmed is returned by writes when a ConfirmPolicy is set and the device doesn't
+// report the written values before the policy's timeout. The values were accepted by Nest
+// and may still reach the device later.
+var ErrNotConfirmed = errors.New("Device did not report the written values in time")
+
+// ConfirmPolicy makes writes wait until the device reports the values that were written,
+// by polling the device after Nest accepts the write
+type ConfirmPolicy struct {
+	// Timeout is how long to wait for the device to report the values, defaults to 30 seconds
+	Timeout time.Duration
+
+	// Interval is the time between polls, defaults to 2 seconds
+	Interval time.Duration
+}
+
+func (c *ConfirmPolicy) timeout() time.Duration {
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+
+	return 30 * time.Second
+}
+
+func (c *ConfirmPolicy) interval() time.Duration {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+
+	return 2 * time.Second
+}
+
+// waitForValues polls the device until it reports every value in applied, the response to
+// a write
+func (n *Connection) waitForValues(deviceType, deviceID string, applied []byte) error {
+	want := make(map[string]interface{})
+
+	err := json.Unmarshal(applied, &want)
+	if err != nil {
+		return fmt.Errorf("%s %s response is invalid: %s", n.toTitleCase(deviceType), deviceID, err)
+	}
+
+	ctx := n.Context()
+	url := n.setURL(fmt.Sprintf("%s/%s", deviceType, deviceID))
+	deadline := time.Now().Add(n.Confirm.timeout())
+
+	for {
+		// Polls always make a request, the cache can't have the values yet
+		data, err := n.send(url, "GET", nil)
+		if err != nil {
+			return err
+		}
+
+		got := make(map[string]interface{})
+
+		err = json.Unmarshal(data, &got)
+		if err != nil {
+			return fmt.Errorf("%s %s is invalid: %s", n.toTitleCase(deviceType), deviceID, err)
+		}
+
+		if reportsValues(got, want) {
+			return nil
+		}
+
+		wait := n.Confirm.interval()
+		if time.Now().Add(wait).After(deadline) {
+			return ErrNotConfirmed
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// reportsValues reports whether every value in want is in got
+func reportsValues(got, want map[string]interface{}) bool {
+	for field, val := range want {
+		if !reflect.DeepEqual(got[field], val) {
+			return false
+		}
+	}
+
+	return true
+}
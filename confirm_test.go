@@ -0,0 +1,223 @@
+package nest
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// createTestConfirmConnection creates a connection to a server that accepts writes but only
+// reports the new hvac_mode after the given number of reads
+func createTestConfirmConnection(reads int) (Connection, *httptest.Server, *int) {
+	polls := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			data, _ := ioutil.ReadAll(r.Body)
+			w.Write(data)
+			return
+		}
+
+		polls++
+		if polls > reads {
+			w.Write([]byte(`{"device_id":"abc","hvac_mode":"cool"}`))
+			return
+		}
+
+		w.Write([]byte(`{"device_id":"abc","hvac_mode":"heat"}`))
+	}))
+
+	return Connection{
+		AccessToken: "TEST",
+		testURL:     fmt.Sprintf("%s/devices", server.URL),
+		Confirm: &ConfirmPolicy{
+			Timeout:  50 * time.Millisecond,
+			Interval: time.Millisecond,
+		},
+	}, server, &polls
+}
+
+func TestConfirmedValues(t *testing.T) {
+	t.Run("Applied value returned", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		mode, err := n.SetHVACMode("abc", HVACModeCool)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := HVACModeCool
+			if mode != expected {
+				t.Fatalf("Expected HVAC Mode to equal %s, got %s", expected, mode)
+			}
+		}
+	})
+
+	t.Run("Applied temperature returned", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		temp, err := n.SetTargetTemperature("def", Celsius(21.2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := Celsius(21)
+			if temp != expected {
+				t.Fatalf("Expected Target Temperature to equal %s, got %s", expected, temp)
+			}
+		}
+	})
+
+	t.Run("Value missing from response", func(t *testing.T) {
+		n, server := createTestErrorConnection(200, "{}")
+		defer server.Close()
+
+		_, err := n.SetHVACMode("abc", HVACModeCool)
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Thermostat abc did not confirm hvac_mode"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	for _, body := range []string{`{"target_temperature_f":null}`, "null"} {
+		t.Run("Null response "+body, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == "GET" {
+					w.Write(generateTestData(r.URL.String()))
+					return
+				}
+
+				w.Write([]byte(body))
+			}))
+			defer server.Close()
+
+			n := Connection{
+				AccessToken: "TEST",
+				testURL:     fmt.Sprintf("%s/devices", server.URL),
+			}
+
+			_, err := n.SetTargetTemperatureF("def", 70)
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+
+			{
+				expected := "Thermostat def did not confirm target_temperature_f"
+				if err.Error() != expected {
+					t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+				}
+			}
+		})
+	}
+
+	t.Run("Streaming", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		streaming, err := n.TurnOnStreaming("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := true
+			if streaming != expected {
+				t.Fatalf("Expected streaming to equal %t, got %t", expected, streaming)
+			}
+		}
+	})
+
+	t.Run("Fan timer off", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		active, err := n.TurnOffFanTimer("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := false
+			if active != expected {
+				t.Fatalf("Expected fan timer active to equal %t, got %t", expected, active)
+			}
+		}
+	})
+
+	t.Run("ETA", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		begin := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+		end := begin.Add(30 * time.Minute)
+
+		gotBegin, gotEnd, err := n.SetStructureETA("abc", "trip1", begin, end)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !gotBegin.Equal(begin) || !gotEnd.Equal(end) {
+			t.Fatalf("Expected window to equal %v - %v, got %v - %v", begin, end, gotBegin, gotEnd)
+		}
+	})
+}
+
+func TestConfirmPolicy(t *testing.T) {
+	t.Run("Waits until reported", func(t *testing.T) {
+		n, server, polls := createTestConfirmConnection(2)
+		defer server.Close()
+
+		_, err := n.SetHVACMode("abc", HVACModeCool)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 3
+			if *polls != expected {
+				t.Fatalf("Expected %d poll(s), got %d", expected, *polls)
+			}
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		n, server, _ := createTestConfirmConnection(1000)
+		defer server.Close()
+
+		_, err := n.SetHVACMode("abc", HVACModeCool)
+		if !errors.Is(err, ErrNotConfirmed) {
+			t.Fatalf("Expected errors.Is(%v), got %v", ErrNotConfirmed, err)
+		}
+	})
+
+	t.Run("Not used for write only fields", func(t *testing.T) {
+		n, server, polls := createTestConfirmConnection(1000)
+		defer server.Close()
+
+		err := n.CancelStructureETA("abc", "trip1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 0
+			if *polls != expected {
+				t.Fatalf("Expected %d poll(s), got %d", expected, *polls)
+			}
+		}
+	})
+}
@@ -0,0 +1,407 @@
+package nest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DeviceKind is the type of a device, named as in the data model
+type DeviceKind string
+
+// Kinds of device
+const (
+	DeviceKindThermostat   DeviceKind = "thermostats"
+	DeviceKindSmokeCOAlarm DeviceKind = "smoke_co_alarms"
+	DeviceKindCamera       DeviceKind = "cameras"
+)
+
+// ConnectivityTransition is a device going online or offline. Time is when the device
+// reports the change happened where it can, otherwise when the change was seen.
+type ConnectivityTransition struct {
+	Kind     DeviceKind
+	DeviceID string
+	Name     string
+	Online   bool
+	Time     time.Time
+}
+
+// OfflineEvent is a device that has been offline for longer than the tracker's threshold
+type OfflineEvent struct {
+	Kind     DeviceKind
+	DeviceID string
+	Name     string
+	Since    time.Time
+	Duration time.Duration
+}
+
+type deviceConnectivity struct {
+	kind   DeviceKind
+	name   string
+	online bool
+	since  time.Time
+
+	// Connectivity history, the device was in state start from startTime until the first
+	// of transitions
+	startTime   time.Time
+	start       bool
+	transitions []ConnectivityTransition
+
+	// Whether an offline event has been sent for the current time offline
+	reported bool
+}
+
+// ConnectivityTracker records when thermostats, smoke/co alarms and cameras go online
+// and offline. Only a change in a device's is_online adds a transition, so every snapshot
+// or stream update containing the device can be passed in. Devices are identified by
+// their device ID, which is unique across every type of device. The zero value is ready
+// to use.
+type ConnectivityTracker struct {
+	// OfflineThreshold is how long a device has to be offline before an offline event is
+	// sent for it. Defaults to 10 minutes.
+	OfflineThreshold time.Duration
+
+	// Retention is how long transitions are kept for. Defaults to 30 days.
+	Retention time.Duration
+
+	mu              sync.Mutex
+	handlers        []func(ConnectivityTransition)
+	offlineHandlers []func(OfflineEvent)
+	devices         map[string]*deviceConnectivity
+}
+
+func (c *ConnectivityTracker) offlineThreshold() time.Duration {
+	if c.OfflineThreshold > 0 {
+		return c.OfflineThreshold
+	}
+
+	return 10 * time.Minute
+}
+
+func (c *ConnectivityTracker) retention() time.Duration {
+	if c.Retention > 0 {
+		return c.Retention
+	}
+
+	return 30 * 24 * time.Hour
+}
+
+// OnTransition registers handler to be called whenever a device goes online or offline.
+// The first state seen for a device isn't a transition. Handlers run synchronously inside
+// the Observe call or Watch loop that saw the change, so a slow handler holds up the next
+// device being recorded.
+func (c *ConnectivityTracker) OnTransition(handler func(ConnectivityTransition)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handlers = append(c.handlers, handler)
+}
+
+// OnOffline registers handler to be called once each time a device has been offline for
+// longer than OfflineThreshold. Devices are checked whenever they're observed and by
+// CheckOffline.
+func (c *ConnectivityTracker) OnOffline(handler func(OfflineEvent)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.offlineHandlers = append(c.offlineHandlers, handler)
+}
+
+// ObserveThermostat records whether thermostat is online. LastConnection is used as the
+// time the thermostat went offline.
+func (c *ConnectivityTracker) ObserveThermostat(thermostat Thermostat) {
+	c.observeAt(DeviceKindThermostat, thermostat.DeviceID, thermostat.NameLong, thermostat.IsOnline, offlineSince(thermostat.IsOnline, thermostat.LastConnection), time.Now())
+}
+
+// ObserveSmokeCOAlarm records whether alarm is online. LastConnection is used as the time
+// the alarm went offline.
+func (c *ConnectivityTracker) ObserveSmokeCOAlarm(alarm SmokeCOAlarm) {
+	c.observeAt(DeviceKindSmokeCOAlarm, alarm.DeviceID, alarm.NameLong, alarm.IsOnline, offlineSince(alarm.IsOnline, alarm.LastConnection), time.Now())
+}
+
+// ObserveCamera records whether camera is online. LastIsOnlineChange is used as the time
+// the camera went online or offline.
+func (c *ConnectivityTracker) ObserveCamera(camera Camera) {
+	c.observeAt(DeviceKindCamera, camera.DeviceID, camera.NameLong, camera.IsOnline, camera.LastIsOnlineChange, time.Now())
+}
+
+// ObserveSnapshot records whether every device in the snapshot is online
+func (c *ConnectivityTracker) ObserveSnapshot(snapshot Snapshot) {
+	for _, thermostat := range snapshot.Thermostats {
+		c.ObserveThermostat(thermostat)
+	}
+
+	for _, alarm := range snapshot.SmokeCOAlarms {
+		c.ObserveSmokeCOAlarm(alarm)
+	}
+
+	for _, camera := range snapshot.Cameras {
+		c.ObserveCamera(camera)
+	}
+}
+
+// Refresh fetches every device with a single request and records whether they're online
+func (c *ConnectivityTracker) Refresh(n *Connection) error {
+	snapshot, err := n.GetAll()
+	if err != nil {
+		return err
+	}
+
+	c.ObserveSnapshot(snapshot)
+
+	return nil
+}
+
+// Watch records every device received on the channels until they're all closed or ctx is
+// cancelled. They can come from a StreamFanout subscription to Thermostats, SmokeCOAlarms
+// and Cameras, a Stream's own channels can't be used as its Structures and Errors need
+// reading too. A device that goes offline isn't necessarily sent again, so CheckOffline
+// is also called periodically while watching.
+func (c *ConnectivityTracker) Watch(ctx context.Context, thermostats <-chan Thermostat, alarms <-chan SmokeCOAlarm, cameras <-chan Camera) error {
+	// Check often enough to report devices soon after the threshold, without spinning on
+	// very short thresholds
+	interval := c.offlineThreshold() / 2
+	if interval > time.Minute {
+		interval = time.Minute
+	}
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for thermostats != nil || alarms != nil || cameras != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.CheckOffline()
+		case thermostat, ok := <-thermostats:
+			if !ok {
+				thermostats = nil
+				continue
+			}
+
+			c.ObserveThermostat(thermostat)
+		case alarm, ok := <-alarms:
+			if !ok {
+				alarms = nil
+				continue
+			}
+
+			c.ObserveSmokeCOAlarm(alarm)
+		case camera, ok := <-cameras:
+			if !ok {
+				cameras = nil
+				continue
+			}
+
+			c.ObserveCamera(camera)
+		}
+	}
+
+	return nil
+}
+
+// CheckOffline sends an offline event for every device that has been offline for longer
+// than OfflineThreshold and hasn't been reported yet
+func (c *ConnectivityTracker) CheckOffline() {
+	c.checkOfflineAt(time.Now())
+}
+
+// IsOnline returns whether the device was online when it was last observed, and whether
+// it has been observed
+func (c *ConnectivityTracker) IsOnline(deviceID string) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d, ok := c.devices[deviceID]
+	if !ok {
+		return false, false
+	}
+
+	return d.online, true
+}
+
+// Transitions returns the recorded transitions of the device, oldest first
+func (c *ConnectivityTracker) Transitions(deviceID string) []ConnectivityTransition {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	transitions := []ConnectivityTransition{}
+	if d, ok := c.devices[deviceID]; ok {
+		transitions = append(transitions, d.transitions...)
+	}
+
+	return transitions
+}
+
+// Uptime returns the percentage of the window up to now the device was online. Only the
+// part of the window the tracker knows about is counted, so for a device first seen an
+// hour ago a window of a day gives its uptime over the last hour.
+func (c *ConnectivityTracker) Uptime(deviceID string, window time.Duration) (float64, error) {
+	return c.uptimeAt(deviceID, window, time.Now())
+}
+
+// offlineSince returns when a thermostat or smoke/co alarm that's offline went offline.
+// They only report when they last connected, which says nothing about when one that's
+// online came back.
+func offlineSince(online bool, lastConnection time.Time) time.Time {
+	if online {
+		return time.Time{}
+	}
+
+	return lastConnection
+}
+
+// observeAt records the state of a device as of now. changed is when the device reports
+// the state started, which is zero if it's unknown.
+func (c *ConnectivityTracker) observeAt(kind DeviceKind, deviceID, name string, online bool, changed, now time.Time) {
+	c.mu.Lock()
+	if c.devices == nil {
+		c.devices = make(map[string]*deviceConnectivity)
+	}
+
+	var transition *ConnectivityTransition
+
+	d, seen := c.devices[deviceID]
+	if !seen {
+		since := now
+		if !changed.IsZero() && !changed.After(now) {
+			since = changed
+		}
+
+		d = &deviceConnectivity{kind: kind, online: online, since: since, startTime: since, start: online}
+		c.devices[deviceID] = d
+	} else if d.online != online {
+		// The reported time is only trusted if it's after the last change and not in the future
+		at := now
+		if !changed.IsZero() && changed.After(d.since) && !changed.After(now) {
+			at = changed
+		}
+
+		transition = &ConnectivityTransition{Kind: kind, DeviceID: deviceID, Name: name, Online: online, Time: at}
+
+		d.online = online
+		d.since = at
+		d.reported = false
+		d.transitions = append(d.transitions, *transition)
+	}
+	d.name = name
+
+	c.prune(d, now)
+
+	handlers := c.handlers
+	c.mu.Unlock()
+
+	if transition != nil {
+		for _, handler := range handlers {
+			handler(*transition)
+		}
+	}
+
+	c.checkOfflineAt(now)
+}
+
+// prune drops the transitions that are older than the retention period
+func (c *ConnectivityTracker) prune(d *deviceConnectivity, now time.Time) {
+	cutoff := now.Add(-c.retention())
+
+	for len(d.transitions) > 0 && d.transitions[0].Time.Before(cutoff) {
+		d.start = d.transitions[0].Online
+		d.startTime = d.transitions[0].Time
+		d.transitions = d.transitions[1:]
+	}
+
+	if d.startTime.Before(cutoff) {
+		d.startTime = cutoff
+	}
+}
+
+// checkOfflineAt sends offline events for the devices that have been offline for longer
+// than the threshold as of now
+func (c *ConnectivityTracker) checkOfflineAt(now time.Time) {
+	events := []OfflineEvent{}
+
+	c.mu.Lock()
+	for deviceID, d := range c.devices {
+		if d.online || d.reported {
+			continue
+		}
+
+		offline := now.Sub(d.since)
+		if offline < c.offlineThreshold() {
+			continue
+		}
+
+		d.reported = true
+		events = append(events, OfflineEvent{Kind: d.kind, DeviceID: deviceID, Name: d.name, Since: d.since, Duration: offline})
+	}
+
+	handlers := c.offlineHandlers
+	c.mu.Unlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].DeviceID < events[j].DeviceID
+	})
+
+	for _, e := range events {
+		for _, handler := range handlers {
+			handler(e)
+		}
+	}
+}
+
+// uptimeAt works out the uptime of the device over the window up to now
+func (c *ConnectivityTracker) uptimeAt(deviceID string, window time.Duration, now time.Time) (float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	d, ok := c.devices[deviceID]
+	if !ok {
+		return 0, fmt.Errorf("Device %s has not been observed", deviceID)
+	}
+
+	from := now.Add(-window)
+	if from.Before(d.startTime) {
+		from = d.startTime
+	}
+
+	if !now.After(from) {
+		// Nothing is known about the window yet apart from the current state
+		if d.online {
+			return 100, nil
+		}
+		return 0, nil
+	}
+
+	var up time.Duration
+
+	// Add the part of [start, end) in the window if the device was online
+	add := func(start, end time.Time, online bool) {
+		if !online {
+			return
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(now) {
+			end = now
+		}
+		if end.After(start) {
+			up += end.Sub(start)
+		}
+	}
+
+	state, at := d.start, d.startTime
+	for _, t := range d.transitions {
+		add(at, t.Time, state)
+		state, at = t.Online, t.Time
+	}
+	add(at, now, state)
+
+	return float64(up) / float64(now.Sub(from)) * 100, nil
+}
@@ -0,0 +1,275 @@
+package nest
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestConnectivityTracker(t *testing.T) {
+	start := parseTestTime("2019-01-02T12:00:00.000Z")
+
+	t.Run("Transitions", func(t *testing.T) {
+		c := &ConnectivityTracker{}
+
+		transitions := []ConnectivityTransition{}
+		c.OnTransition(func(tr ConnectivityTransition) {
+			transitions = append(transitions, tr)
+		})
+
+		c.observeAt(DeviceKindThermostat, "abc", "Hallway", true, time.Time{}, start)
+		c.observeAt(DeviceKindThermostat, "abc", "Hallway", true, time.Time{}, start.Add(time.Minute))
+		c.observeAt(DeviceKindThermostat, "abc", "Hallway", false, start.Add(2*time.Minute), start.Add(5*time.Minute))
+		c.observeAt(DeviceKindThermostat, "abc", "Hallway", true, time.Time{}, start.Add(10*time.Minute))
+
+		{
+			expected := 2
+			if len(transitions) != expected {
+				t.Fatalf("Expected %d transition(s), got %d", expected, len(transitions))
+			}
+		}
+
+		// Going offline uses the reported time, coming back uses the time it was seen
+		{
+			expected := start.Add(2 * time.Minute)
+			if transitions[0].Online || !transitions[0].Time.Equal(expected) {
+				t.Fatalf("Expected an offline transition at %s, got %+v", expected, transitions[0])
+			}
+		}
+
+		{
+			expected := start.Add(10 * time.Minute)
+			if !transitions[1].Online || !transitions[1].Time.Equal(expected) {
+				t.Fatalf("Expected an online transition at %s, got %+v", expected, transitions[1])
+			}
+		}
+
+		{
+			expected := 2
+			if len(c.Transitions("abc")) != expected {
+				t.Fatalf("Expected %d recorded transition(s), got %d", expected, len(c.Transitions("abc")))
+			}
+		}
+	})
+
+	t.Run("Reported time ignored", func(t *testing.T) {
+		c := &ConnectivityTracker{}
+
+		c.observeAt(DeviceKindCamera, "abc", "Front door", true, start.Add(time.Hour), start)
+		c.observeAt(DeviceKindCamera, "abc", "Front door", false, start.Add(-time.Hour), start.Add(time.Minute))
+
+		// A reported time before the last change can't be right
+		{
+			expected := start.Add(time.Minute)
+			tr := c.Transitions("abc")[0]
+			if !tr.Time.Equal(expected) {
+				t.Fatalf("Expected transition time to equal %s, got %s", expected, tr.Time)
+			}
+		}
+	})
+
+	t.Run("Offline events", func(t *testing.T) {
+		c := &ConnectivityTracker{OfflineThreshold: 10 * time.Minute}
+
+		events := []OfflineEvent{}
+		c.OnOffline(func(e OfflineEvent) {
+			events = append(events, e)
+		})
+
+		c.observeAt(DeviceKindSmokeCOAlarm, "abc", "Kitchen", true, time.Time{}, start)
+		c.observeAt(DeviceKindSmokeCOAlarm, "abc", "Kitchen", false, start.Add(time.Minute), start.Add(2*time.Minute))
+
+		c.checkOfflineAt(start.Add(5 * time.Minute))
+
+		{
+			expected := 0
+			if len(events) != expected {
+				t.Fatalf("Expected %d event(s), got %d", expected, len(events))
+			}
+		}
+
+		c.checkOfflineAt(start.Add(15 * time.Minute))
+		c.checkOfflineAt(start.Add(20 * time.Minute))
+
+		{
+			expected := 1
+			if len(events) != expected {
+				t.Fatalf("Expected %d event(s), got %d", expected, len(events))
+			}
+		}
+
+		{
+			expected := 14 * time.Minute
+			if events[0].Duration != expected || events[0].DeviceID != "abc" {
+				t.Fatalf("Expected abc to be offline for %s, got %+v", expected, events[0])
+			}
+		}
+
+		// Another event is sent the next time it goes offline
+		c.observeAt(DeviceKindSmokeCOAlarm, "abc", "Kitchen", true, time.Time{}, start.Add(25*time.Minute))
+		c.observeAt(DeviceKindSmokeCOAlarm, "abc", "Kitchen", false, time.Time{}, start.Add(30*time.Minute))
+		c.checkOfflineAt(start.Add(time.Hour))
+
+		{
+			expected := 2
+			if len(events) != expected {
+				t.Fatalf("Expected %d event(s), got %d", expected, len(events))
+			}
+		}
+	})
+
+	t.Run("First seen offline", func(t *testing.T) {
+		c := &ConnectivityTracker{}
+
+		events := []OfflineEvent{}
+		c.OnOffline(func(e OfflineEvent) {
+			events = append(events, e)
+		})
+
+		c.observeAt(DeviceKindThermostat, "abc", "Hallway", false, start.Add(-time.Hour), start)
+
+		{
+			expected := 1
+			if len(events) != expected {
+				t.Fatalf("Expected %d event(s), got %d", expected, len(events))
+			}
+		}
+	})
+
+	t.Run("Uptime", func(t *testing.T) {
+		c := &ConnectivityTracker{}
+
+		c.observeAt(DeviceKindCamera, "abc", "Front door", true, start, start)
+		c.observeAt(DeviceKindCamera, "abc", "Front door", false, start.Add(6*time.Hour), start.Add(6*time.Hour))
+		c.observeAt(DeviceKindCamera, "abc", "Front door", true, start.Add(9*time.Hour), start.Add(9*time.Hour))
+
+		tests := []struct {
+			window   time.Duration
+			expected float64
+		}{
+			{12 * time.Hour, 75},
+			{6 * time.Hour, 50},
+			{3 * time.Hour, 100},
+			// Only the 12 hours since the camera was first seen are counted
+			{24 * time.Hour, 75},
+		}
+
+		for _, test := range tests {
+			uptime, err := c.uptimeAt("abc", test.window, start.Add(12*time.Hour))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if math.Abs(uptime-test.expected) > 0.001 {
+				t.Fatalf("Expected uptime over %s to equal %g, got %g", test.window, test.expected, uptime)
+			}
+		}
+	})
+
+	t.Run("Uptime of unknown device", func(t *testing.T) {
+		c := &ConnectivityTracker{}
+
+		_, err := c.Uptime("abc", time.Hour)
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		c := &ConnectivityTracker{Retention: 24 * time.Hour}
+
+		c.observeAt(DeviceKindCamera, "abc", "Front door", true, start, start)
+		c.observeAt(DeviceKindCamera, "abc", "Front door", false, start.Add(time.Hour), start.Add(time.Hour))
+		c.observeAt(DeviceKindCamera, "abc", "Front door", true, start.Add(2*time.Hour), start.Add(2*time.Hour))
+		c.observeAt(DeviceKindCamera, "abc", "Front door", true, time.Time{}, start.Add(48*time.Hour))
+
+		{
+			expected := 0
+			if len(c.Transitions("abc")) != expected {
+				t.Fatalf("Expected %d recorded transition(s), got %d", expected, len(c.Transitions("abc")))
+			}
+		}
+
+		uptime, err := c.uptimeAt("abc", 72*time.Hour, start.Add(48*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 100.0
+			if uptime != expected {
+				t.Fatalf("Expected uptime to equal %g, got %g", expected, uptime)
+			}
+		}
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		c := &ConnectivityTracker{}
+
+		err := c.Refresh(&n)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		online, seen := c.IsOnline("abc")
+		if !seen {
+			t.Fatal("Expected device abc to have been observed")
+		}
+
+		{
+			expected := true
+			if online != expected {
+				t.Fatalf("Expected device abc online to equal %t, got %t", expected, online)
+			}
+		}
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		c := &ConnectivityTracker{}
+
+		thermostats := make(chan Thermostat, 2)
+		alarms := make(chan SmokeCOAlarm, 1)
+		cameras := make(chan Camera, 1)
+
+		thermostats <- Thermostat{DeviceID: "abc", IsOnline: true}
+		thermostats <- Thermostat{DeviceID: "abc", IsOnline: false}
+		alarms <- SmokeCOAlarm{DeviceID: "def", IsOnline: true}
+		cameras <- Camera{DeviceID: "ghi", IsOnline: false}
+		close(thermostats)
+		close(alarms)
+		close(cameras)
+
+		err := c.Watch(context.Background(), thermostats, alarms, cameras)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, deviceID := range []string{"abc", "def", "ghi"} {
+			if _, seen := c.IsOnline(deviceID); !seen {
+				t.Fatalf("Expected device %s to have been observed", deviceID)
+			}
+		}
+
+		{
+			expected := 1
+			if len(c.Transitions("abc")) != expected {
+				t.Fatalf("Expected %d recorded transition(s), got %d", expected, len(c.Transitions("abc")))
+			}
+		}
+	})
+	t.Run("Watch with tiny threshold", func(t *testing.T) {
+		c := &ConnectivityTracker{OfflineThreshold: time.Nanosecond}
+
+		thermostats := make(chan Thermostat)
+		close(thermostats)
+
+		err := c.Watch(context.Background(), thermostats, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+}
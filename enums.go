@@ -0,0 +1,366 @@
+package nest
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// isEnumValue reports whether val is one of valid
+func isEnumValue(val string, valid []string) bool {
+	for _, v := range valid {
+		if val == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validateEnum returns an error unless val is one of valid
+func validateEnum(name, val string, valid []string) error {
+	if val == "" {
+		return fmt.Errorf("%s must not be empty", name)
+	}
+
+	if !isEnumValue(val, valid) {
+		return fmt.Errorf("%s must be one of the following: %s", name, valid)
+	}
+
+	return nil
+}
+
+// marshalEnum encodes val, which must be empty, unknown or one of valid
+func marshalEnum(name, val string, valid []string) ([]byte, error) {
+	if val != "" && val != "unknown" && !isEnumValue(val, valid) {
+		return nil, fmt.Errorf("%s must be one of the following: %s", name, valid)
+	}
+
+	return json.Marshal(val)
+}
+
+// unmarshalEnum decodes a value, mapping any value that isn't one of valid to unknown
+func unmarshalEnum(data []byte, valid []string) (string, error) {
+	var val string
+
+	err := json.Unmarshal(data, &val)
+	if err != nil {
+		return "", err
+	}
+
+	if val != "" && !isEnumValue(val, valid) {
+		return "unknown", nil
+	}
+
+	return val, nil
+}
+
+// TemperatureScale is the scale temperatures are displayed in by a thermostat
+type TemperatureScale string
+
+// TemperatureScale values, TemperatureScaleUnknown is used for values this package doesn't know about
+const (
+	TemperatureScaleF       TemperatureScale = "F"
+	TemperatureScaleC       TemperatureScale = "C"
+	TemperatureScaleUnknown TemperatureScale = "unknown"
+)
+
+var temperatureScales = []string{"F", "C"}
+
+func (t TemperatureScale) String() string {
+	return string(t)
+}
+
+// Validate returns an error unless t is a known value
+func (t TemperatureScale) Validate() error {
+	return validateEnum("Temperature Scale", string(t), temperatureScales)
+}
+
+// MarshalJSON encodes t, returning an error if it isn't a known value
+func (t TemperatureScale) MarshalJSON() ([]byte, error) {
+	return marshalEnum("Temperature Scale", string(t), temperatureScales)
+}
+
+// UnmarshalJSON decodes t, values that aren't known are decoded as TemperatureScaleUnknown
+func (t *TemperatureScale) UnmarshalJSON(data []byte) error {
+	val, err := unmarshalEnum(data, temperatureScales)
+	if err != nil {
+		return err
+	}
+
+	*t = TemperatureScale(val)
+
+	return nil
+}
+
+// HVACMode is the mode of a thermostat's heating and cooling system
+type HVACMode string
+
+// HVACMode values, HVACModeUnknown is used for values this package doesn't know about
+const (
+	HVACModeHeat     HVACMode = "heat"
+	HVACModeCool     HVACMode = "cool"
+	HVACModeHeatCool HVACMode = "heat-cool"
+	HVACModeEco      HVACMode = "eco"
+	HVACModeOff      HVACMode = "off"
+	HVACModeUnknown  HVACMode = "unknown"
+)
+
+var hvacModes = []string{"heat", "cool", "heat-cool", "eco", "off"}
+
+func (m HVACMode) String() string {
+	return string(m)
+}
+
+// Validate returns an error unless m is a known value
+func (m HVACMode) Validate() error {
+	return validateEnum("HVAC Mode", string(m), hvacModes)
+}
+
+// MarshalJSON encodes m, returning an error if it isn't a known value
+func (m HVACMode) MarshalJSON() ([]byte, error) {
+	return marshalEnum("HVAC Mode", string(m), hvacModes)
+}
+
+// UnmarshalJSON decodes m, values that aren't known are decoded as HVACModeUnknown
+func (m *HVACMode) UnmarshalJSON(data []byte) error {
+	val, err := unmarshalEnum(data, hvacModes)
+	if err != nil {
+		return err
+	}
+
+	*m = HVACMode(val)
+
+	return nil
+}
+
+// HVACState is whether a thermostat's heating and cooling system is actively heating, cooling or neither
+type HVACState string
+
+// HVACState values, HVACStateUnknown is used for values this package doesn't know about
+const (
+	HVACStateHeating HVACState = "heating"
+	HVACStateCooling HVACState = "cooling"
+	HVACStateOff     HVACState = "off"
+	HVACStateUnknown HVACState = "unknown"
+)
+
+var hvacStates = []string{"heating", "cooling", "off"}
+
+func (s HVACState) String() string {
+	return string(s)
+}
+
+// Validate returns an error unless s is a known value
+func (s HVACState) Validate() error {
+	return validateEnum("HVAC State", string(s), hvacStates)
+}
+
+// MarshalJSON encodes s, returning an error if it isn't a known value
+func (s HVACState) MarshalJSON() ([]byte, error) {
+	return marshalEnum("HVAC State", string(s), hvacStates)
+}
+
+// UnmarshalJSON decodes s, values that aren't known are decoded as HVACStateUnknown
+func (s *HVACState) UnmarshalJSON(data []byte) error {
+	val, err := unmarshalEnum(data, hvacStates)
+	if err != nil {
+		return err
+	}
+
+	*s = HVACState(val)
+
+	return nil
+}
+
+// AlarmState is the state of a smoke or CO alarm
+type AlarmState string
+
+// AlarmState values, AlarmStateUnknown is used for values this package doesn't know about
+const (
+	AlarmStateOK        AlarmState = "ok"
+	AlarmStateWarning   AlarmState = "warning"
+	AlarmStateEmergency AlarmState = "emergency"
+	AlarmStateUnknown   AlarmState = "unknown"
+)
+
+var alarmStates = []string{"ok", "warning", "emergency"}
+
+func (a AlarmState) String() string {
+	return string(a)
+}
+
+// Validate returns an error unless a is a known value
+func (a AlarmState) Validate() error {
+	return validateEnum("Alarm State", string(a), alarmStates)
+}
+
+// MarshalJSON encodes a, returning an error if it isn't a known value
+func (a AlarmState) MarshalJSON() ([]byte, error) {
+	return marshalEnum("Alarm State", string(a), alarmStates)
+}
+
+// UnmarshalJSON decodes a, values that aren't known are decoded as AlarmStateUnknown
+func (a *AlarmState) UnmarshalJSON(data []byte) error {
+	val, err := unmarshalEnum(data, alarmStates)
+	if err != nil {
+		return err
+	}
+
+	*a = AlarmState(val)
+
+	return nil
+}
+
+// BatteryHealth is the battery health of a smoke/co alarm
+type BatteryHealth string
+
+// BatteryHealth values, BatteryHealthUnknown is used for values this package doesn't know about
+const (
+	BatteryHealthOK      BatteryHealth = "ok"
+	BatteryHealthReplace BatteryHealth = "replace"
+	BatteryHealthUnknown BatteryHealth = "unknown"
+)
+
+var batteryHealths = []string{"ok", "replace"}
+
+func (b BatteryHealth) String() string {
+	return string(b)
+}
+
+// Validate returns an error unless b is a known value
+func (b BatteryHealth) Validate() error {
+	return validateEnum("Battery Health", string(b), batteryHealths)
+}
+
+// MarshalJSON encodes b, returning an error if it isn't a known value
+func (b BatteryHealth) MarshalJSON() ([]byte, error) {
+	return marshalEnum("Battery Health", string(b), batteryHealths)
+}
+
+// UnmarshalJSON decodes b, values that aren't known are decoded as BatteryHealthUnknown
+func (b *BatteryHealth) UnmarshalJSON(data []byte) error {
+	val, err := unmarshalEnum(data, batteryHealths)
+	if err != nil {
+		return err
+	}
+
+	*b = BatteryHealth(val)
+
+	return nil
+}
+
+// UIColorState is the color of a smoke/co alarm's ring
+type UIColorState string
+
+// UIColorState values, UIColorStateUnknown is used for values this package doesn't know about
+const (
+	UIColorStateGray    UIColorState = "gray"
+	UIColorStateGreen   UIColorState = "green"
+	UIColorStateYellow  UIColorState = "yellow"
+	UIColorStateRed     UIColorState = "red"
+	UIColorStateUnknown UIColorState = "unknown"
+)
+
+var uiColorStates = []string{"gray", "green", "yellow", "red"}
+
+func (u UIColorState) String() string {
+	return string(u)
+}
+
+// Validate returns an error unless u is a known value
+func (u UIColorState) Validate() error {
+	return validateEnum("UI Color State", string(u), uiColorStates)
+}
+
+// MarshalJSON encodes u, returning an error if it isn't a known value
+func (u UIColorState) MarshalJSON() ([]byte, error) {
+	return marshalEnum("UI Color State", string(u), uiColorStates)
+}
+
+// UnmarshalJSON decodes u, values that aren't known are decoded as UIColorStateUnknown
+func (u *UIColorState) UnmarshalJSON(data []byte) error {
+	val, err := unmarshalEnum(data, uiColorStates)
+	if err != nil {
+		return err
+	}
+
+	*u = UIColorState(val)
+
+	return nil
+}
+
+// SecurityState is the security state of a structure
+type SecurityState string
+
+// SecurityState values, SecurityStateUnknown is used for values this package doesn't know about
+const (
+	SecurityStateOK      SecurityState = "ok"
+	SecurityStateDeter   SecurityState = "deter"
+	SecurityStateUnknown SecurityState = "unknown"
+)
+
+var securityStates = []string{"ok", "deter"}
+
+func (s SecurityState) String() string {
+	return string(s)
+}
+
+// Validate returns an error unless s is a known value
+func (s SecurityState) Validate() error {
+	return validateEnum("Security State", string(s), securityStates)
+}
+
+// MarshalJSON encodes s, returning an error if it isn't a known value
+func (s SecurityState) MarshalJSON() ([]byte, error) {
+	return marshalEnum("Security State", string(s), securityStates)
+}
+
+// UnmarshalJSON decodes s, values that aren't known are decoded as SecurityStateUnknown
+func (s *SecurityState) UnmarshalJSON(data []byte) error {
+	val, err := unmarshalEnum(data, securityStates)
+	if err != nil {
+		return err
+	}
+
+	*s = SecurityState(val)
+
+	return nil
+}
+
+// AwayState is the occupancy state of a structure
+type AwayState string
+
+// AwayState values, AwayStateUnknown is used for values this package doesn't know about
+const (
+	AwayStateHome    AwayState = "home"
+	AwayStateAway    AwayState = "away"
+	AwayStateUnknown AwayState = "unknown"
+)
+
+var awayStates = []string{"home", "away"}
+
+func (a AwayState) String() string {
+	return string(a)
+}
+
+// Validate returns an error unless a is a known value
+func (a AwayState) Validate() error {
+	return validateEnum("Away", string(a), awayStates)
+}
+
+// MarshalJSON encodes a, returning an error if it isn't a known value
+func (a AwayState) MarshalJSON() ([]byte, error) {
+	return marshalEnum("Away", string(a), awayStates)
+}
+
+// UnmarshalJSON decodes a, values that aren't known are decoded as AwayStateUnknown
+func (a *AwayState) UnmarshalJSON(data []byte) error {
+	val, err := unmarshalEnum(data, awayStates)
+	if err != nil {
+		return err
+	}
+
+	*a = AwayState(val)
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package nest
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEnumUnmarshal(t *testing.T) {
+	t.Run("Known values", func(t *testing.T) {
+		thermostat := Thermostat{}
+
+		err := json.Unmarshal([]byte(`{"hvac_mode":"heat-cool","hvac_state":"cooling","temperature_scale":"C"}`), &thermostat)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := HVACModeHeatCool
+			if thermostat.HVACMode != expected {
+				t.Fatalf("Expected HVACMode to equal %s, got %s", expected, thermostat.HVACMode)
+			}
+		}
+
+		{
+			expected := HVACStateCooling
+			if thermostat.HVACState != expected {
+				t.Fatalf("Expected HVACState to equal %s, got %s", expected, thermostat.HVACState)
+			}
+		}
+
+		{
+			expected := TemperatureScaleC
+			if thermostat.TemperatureScale != expected {
+				t.Fatalf("Expected TemperatureScale to equal %s, got %s", expected, thermostat.TemperatureScale)
+			}
+		}
+	})
+
+	t.Run("Unknown values", func(t *testing.T) {
+		smokeCOAlarm := SmokeCOAlarm{}
+
+		err := json.Unmarshal([]byte(`{"co_alarm_state":"testing","battery_health":"low","ui_color_state":"blue"}`), &smokeCOAlarm)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := AlarmStateUnknown
+			if smokeCOAlarm.COAlarmState != expected {
+				t.Fatalf("Expected COAlarmState to equal %s, got %s", expected, smokeCOAlarm.COAlarmState)
+			}
+		}
+
+		{
+			expected := BatteryHealthUnknown
+			if smokeCOAlarm.BatteryHealth != expected {
+				t.Fatalf("Expected BatteryHealth to equal %s, got %s", expected, smokeCOAlarm.BatteryHealth)
+			}
+		}
+
+		{
+			expected := UIColorStateUnknown
+			if smokeCOAlarm.UIColorState != expected {
+				t.Fatalf("Expected UIColorState to equal %s, got %s", expected, smokeCOAlarm.UIColorState)
+			}
+		}
+	})
+
+	t.Run("Absent values", func(t *testing.T) {
+		structure := Structure{}
+
+		err := json.Unmarshal([]byte(`{"away":"away"}`), &structure)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := AwayStateAway
+			if structure.Away != expected {
+				t.Fatalf("Expected Away to equal %s, got %s", expected, structure.Away)
+			}
+		}
+
+		{
+			expected := SecurityState("")
+			if structure.WWNSecurityState != expected {
+				t.Fatalf("Expected WWNSecurityState to equal %s, got %s", expected, structure.WWNSecurityState)
+			}
+		}
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		var mode HVACMode
+
+		err := json.Unmarshal([]byte(`1`), &mode)
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+	})
+}
+
+func TestEnumMarshal(t *testing.T) {
+	t.Run("Known value", func(t *testing.T) {
+		data, err := json.Marshal(HVACModeEco)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := `"eco"`
+			if string(data) != expected {
+				t.Fatalf("Expected JSON to equal %s, got %s", expected, string(data))
+			}
+		}
+	})
+
+	t.Run("Invalid value", func(t *testing.T) {
+		_, err := json.Marshal(HVACMode("auto"))
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+	})
+}
+
+func TestEnumValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"Valid", SecurityStateDeter.Validate(), ""},
+		{"Empty", HVACState("").Validate(), "HVAC State must not be empty"},
+		{"Unknown", AwayStateUnknown.Validate(), "Away must be one of the following: [home away]"},
+		{"Invalid", UIColorState("blue").Validate(), "UI Color State must be one of the following: [gray green yellow red]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := ""
+			if test.err != nil {
+				actual = test.err.Error()
+			}
+
+			if actual != test.expected {
+				t.Fatalf("Expected error message to equal %s, got %s", test.expected, actual)
+			}
+		})
+	}
+}
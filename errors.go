@@ -0,0 +1,126 @@
+package nest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors that an APIError can be compared to with errors.Is
+var (
+	ErrNotFound      = errors.New("Not found")
+	ErrUnauthorized  = errors.New("Unauthorized")
+	ErrRateLimited   = errors.New("Rate limited")
+	ErrValidation    = errors.New("Validation failed")
+	ErrDeviceOffline = errors.New("Device offline")
+)
+
+type errorResponse struct {
+	Error    string `json:"error"`
+	Type     string `json:"type"`
+	Message  string `json:"message"`
+	Instance string `json:"instance"`
+}
+
+// APIError is returned when the Nest API responds with an error
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+
+	// Code is the short Nest error, e.g. "blocked" or "unauthorized"
+	Code string
+
+	// Type is the URL of the Nest documentation for the error
+	Type string
+
+	// Message is a description of the error
+	Message string
+
+	// Instance uniquely identifies this occurrence of the error
+	Instance string
+
+	// URL is the URL of the request that failed
+	URL string
+
+	// RetryAfter is how long the API asked to wait before trying again, if it did
+	RetryAfter time.Duration
+}
+
+// newAPIError creates an APIError from an error response and its body
+func newAPIError(resp *http.Response, data []byte, url string) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		URL:        url,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	errMsg := errorResponse{}
+
+	err := json.Unmarshal(data, &errMsg)
+	if err != nil {
+		apiErr.Message = string(data)
+		return apiErr
+	}
+
+	apiErr.Code = errMsg.Error
+	apiErr.Type = errMsg.Type
+	apiErr.Message = errMsg.Message
+	apiErr.Instance = errMsg.Instance
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = e.Code
+	}
+
+	return fmt.Sprintf("Error: %s", msg)
+}
+
+// Is reports whether the error matches one of the sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == 404
+	case ErrUnauthorized:
+		return e.StatusCode == 401 || e.StatusCode == 403
+	case ErrRateLimited:
+		return e.StatusCode == 429 || e.contains("blocked")
+	case ErrDeviceOffline:
+		return e.contains("offline")
+	case ErrValidation:
+		return (e.StatusCode == 400 || e.StatusCode == 422) && !e.contains("offline") && !e.contains("blocked")
+	}
+
+	return false
+}
+
+// contains reports whether the error code or message contains str
+func (e *APIError) contains(str string) bool {
+	return strings.Contains(strings.ToLower(e.Code), str) || strings.Contains(strings.ToLower(e.Message), str)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
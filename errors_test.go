@@ -0,0 +1,126 @@
+package nest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func createTestErrorConnection(statusCode int, body string) (Connection, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+		w.Write([]byte(body))
+	}))
+
+	return Connection{
+		AccessToken: "TEST",
+		testURL:     fmt.Sprintf("%s/devices", server.URL),
+	}, server
+}
+
+func TestAPIError(t *testing.T) {
+	t.Run("Error response", func(t *testing.T) {
+		n, server := createTestErrorConnection(429, `{"error":"blocked","type":"https://developer.nest.com/documentation/cloud/error-messages#blocked","message":"blocked","instance":"abc-123"}`)
+		defer server.Close()
+
+		_, err := n.GetThermostats()
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected an APIError, got %v", err)
+		}
+
+		{
+			expected := 429
+			if apiErr.StatusCode != expected {
+				t.Fatalf("Expected StatusCode to equal %d, got %d", expected, apiErr.StatusCode)
+			}
+		}
+
+		{
+			expected := "blocked"
+			if apiErr.Code != expected {
+				t.Fatalf("Expected Code to equal %s, got %s", expected, apiErr.Code)
+			}
+		}
+
+		{
+			expected := "abc-123"
+			if apiErr.Instance != expected {
+				t.Fatalf("Expected Instance to equal %s, got %s", expected, apiErr.Instance)
+			}
+		}
+
+		{
+			expected := fmt.Sprintf("%s/devices/thermostats", server.URL)
+			if apiErr.URL != expected {
+				t.Fatalf("Expected URL to equal %s, got %s", expected, apiErr.URL)
+			}
+		}
+
+		{
+			expected := "Error: blocked"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Non JSON error response", func(t *testing.T) {
+		n, server := createTestErrorConnection(500, "Internal Server Error")
+		defer server.Close()
+
+		_, err := n.GetThermostat("abc")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Error: Internal Server Error"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		expected   error
+	}{
+		{"Not found", 404, `{"error":"not found","message":"not found"}`, ErrNotFound},
+		{"Unauthorized", 401, `{"error":"unauthorized","message":"unauthorized"}`, ErrUnauthorized},
+		{"Rate limited", 429, `{"error":"blocked","message":"blocked"}`, ErrRateLimited},
+		{"Blocked", 400, `{"error":"blocked","message":"blocked"}`, ErrRateLimited},
+		{"Validation", 400, `{"error":"Invalid value","message":"Invalid value for hvac_mode"}`, ErrValidation},
+		{"Device offline", 400, `{"error":"Device offline","message":"Thermostat is offline"}`, ErrDeviceOffline},
+	}
+
+	sentinels := []error{ErrNotFound, ErrUnauthorized, ErrRateLimited, ErrValidation, ErrDeviceOffline}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n, server := createTestErrorConnection(test.statusCode, test.body)
+			defer server.Close()
+
+			_, err := n.SetHVACMode("abc", "heat")
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+
+			for _, sentinel := range sentinels {
+				if errors.Is(err, sentinel) != (sentinel == test.expected) {
+					t.Fatalf("Expected errors.Is(%v) to equal %t", sentinel, sentinel == test.expected)
+				}
+			}
+		})
+	}
+}
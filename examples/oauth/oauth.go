@@ -0,0 +1,33 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/mattvella07/nest"
+)
+
+func main() {
+	c := nest.OAuthConfig{
+		ClientID:     os.Getenv("nestClientID"),
+		ClientSecret: os.Getenv("nestClientSecret"),
+	}
+
+	fmt.Println("Visit this URL to authorize access, then enter the PIN: ", c.AuthCodeURL("state"))
+
+	pin, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	n, err := c.Exchange(context.Background(), strings.TrimSpace(pin))
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	fmt.Println("Access token: ", n.AccessToken)
+}
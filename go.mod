@@ -1 +1,3 @@
 module github.com/mattvella07/nest
+
+go 1.13
@@ -1,9 +1,72 @@
 package nest
 
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
 // Connection contains important connection info
 type Connection struct {
 	AccessToken string
-	testURL     string
+
+	// TokenExpiry is when the access token expires, it is set by OAuthConfig.Exchange
+	TokenExpiry time.Time
+
+	// HTTPClient is used to make requests, its transport and connection pool are
+	// reused across calls. A client using http.DefaultTransport is used if nil.
+	HTTPClient *http.Client
+
+	// Timeout limits the time each request can take, zero means no timeout
+	Timeout time.Duration
+
+	// UserAgent is sent with each request when set
+	UserAgent string
+
+	// MaxRedirects is the number of redirects followed before a request fails, defaults to 10
+	MaxRedirects int
+
+	// Retry is the policy used to retry failed requests, requests aren't retried if nil
+	Retry *RetryPolicy
+
+	// Limiter limits how often devices and structures are written to, writes aren't
+	// limited if nil
+	Limiter *WriteLimiter
+
+	// Confirm makes writes wait until the device reports the written values, writes
+	// return as soon as Nest accepts them if nil
+	Confirm *ConfirmPolicy
+
+	// Cache serves reads from recent responses and from streams opened by the connection,
+	// every read makes a request if nil
+	Cache *Cache
+
+	testURL string
+	ctx     context.Context
 }
 
 const BaseURL = "https://developer-api.nest.com/devices"
+
+// WithContext returns a copy of the connection that makes all its requests with ctx,
+// so they can be cancelled or given a deadline. Cancelled requests return ctx.Err(),
+// which can be checked for with errors.Is(err, context.Canceled) or
+// errors.Is(err, context.DeadlineExceeded).
+func (n *Connection) WithContext(ctx context.Context) *Connection {
+	if ctx == nil {
+		panic("nil context")
+	}
+
+	n2 := *n
+	n2.ctx = ctx
+
+	return &n2
+}
+
+// Context returns the connection's context, which defaults to context.Background
+func (n *Connection) Context() context.Context {
+	if n.ctx != nil {
+		return n.ctx
+	}
+
+	return context.Background()
+}
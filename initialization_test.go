@@ -0,0 +1,71 @@
+package nest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWithContext(t *testing.T) {
+	t.Run("Context cancelled", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := n.WithContext(ctx).GetThermostats()
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Expected error to be context.Canceled, got %v", err)
+		}
+
+		// The original connection isn't affected
+		_, err = n.GetThermostats()
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Deadline exceeded", func(t *testing.T) {
+		done := make(chan struct{})
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-done
+		}))
+		defer server.Close()
+		defer close(done)
+
+		n := Connection{
+			AccessToken: "TEST",
+			testURL:     fmt.Sprintf("%s/devices", server.URL),
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err := n.WithContext(ctx).SetHVACMode("abc", "heat")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected error to be context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	t.Run("Default context", func(t *testing.T) {
+		n := Connection{}
+
+		if n.Context() != context.Background() {
+			t.Fatal("Expected context to equal context.Background()")
+		}
+	})
+}
@@ -0,0 +1,211 @@
+package nest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FindingKind is the kind of problem a maintenance finding is about
+type FindingKind string
+
+// Kinds of maintenance finding
+const (
+	FindingReplaceBattery   FindingKind = "replace_battery"
+	FindingNeverTested      FindingKind = "never_tested"
+	FindingTestOverdue      FindingKind = "test_overdue"
+	FindingOffline          FindingKind = "offline"
+	FindingOutdatedSoftware FindingKind = "outdated_software"
+)
+
+// MaintenanceFinding is a smoke/co alarm that needs attention
+type MaintenanceFinding struct {
+	Kind     FindingKind `json:"kind"`
+	DeviceID string      `json:"device_id"`
+	Name     string      `json:"name"`
+	Message  string      `json:"message"`
+}
+
+// MaintenanceReport lists the smoke/co alarms in a structure that need attention
+type MaintenanceReport struct {
+	StructureID string               `json:"structure_id"`
+	Name        string               `json:"name"`
+	GeneratedAt time.Time            `json:"generated_at"`
+	Alarms      int                  `json:"alarms"`
+	Findings    []MaintenanceFinding `json:"findings"`
+}
+
+// MaintenanceOptions configures the checks made by MaintenanceReport
+type MaintenanceOptions struct {
+	// TestInterval is how often alarms should be tested manually, alarms that haven't
+	// been tested for longer are reported. Defaults to 30 days.
+	TestInterval time.Duration
+}
+
+func (o MaintenanceOptions) testInterval() time.Duration {
+	if o.TestInterval > 0 {
+		return o.TestInterval
+	}
+
+	return 30 * 24 * time.Hour
+}
+
+// MaintenanceReport checks every smoke/co alarm in the specified structure for a battery
+// that needs replacing, manual tests that are overdue or were never done, being offline,
+// and running older software than the structure's other alarms
+func (n *Connection) MaintenanceReport(structureID string, opts MaintenanceOptions) (MaintenanceReport, error) {
+	// Error checking
+	if strings.Trim(structureID, " ") == "" {
+		return MaintenanceReport{}, errors.New("Structure ID must not be empty")
+	}
+
+	snapshot, err := n.GetAll()
+	if err != nil {
+		return MaintenanceReport{}, err
+	}
+
+	structure, ok := snapshot.Structures[structureID]
+	if !ok {
+		return MaintenanceReport{}, fmt.Errorf("Structure %s not found", structureID)
+	}
+
+	return newMaintenanceReport(structure, snapshot.StructureSmokeCOAlarms(structureID), opts, time.Now()), nil
+}
+
+// newMaintenanceReport checks alarms as of now
+func newMaintenanceReport(structure Structure, alarms []SmokeCOAlarm, opts MaintenanceOptions, now time.Time) MaintenanceReport {
+	report := MaintenanceReport{
+		StructureID: structure.StructureID,
+		Name:        structure.Name,
+		GeneratedAt: now,
+		Alarms:      len(alarms),
+		Findings:    []MaintenanceFinding{},
+	}
+
+	sort.Slice(alarms, func(i, j int) bool {
+		if alarms[i].NameLong != alarms[j].NameLong {
+			return alarms[i].NameLong < alarms[j].NameLong
+		}
+		return alarms[i].DeviceID < alarms[j].DeviceID
+	})
+
+	latest := ""
+	for _, alarm := range alarms {
+		if compareVersions(alarm.SoftwareVersion, latest) > 0 {
+			latest = alarm.SoftwareVersion
+		}
+	}
+
+	for _, alarm := range alarms {
+		add := func(kind FindingKind, format string, a ...interface{}) {
+			report.Findings = append(report.Findings, MaintenanceFinding{
+				Kind:     kind,
+				DeviceID: alarm.DeviceID,
+				Name:     alarm.NameLong,
+				Message:  fmt.Sprintf(format, a...),
+			})
+		}
+
+		if !alarm.IsOnline {
+			if alarm.LastConnection.IsZero() {
+				add(FindingOffline, "Offline")
+			} else {
+				add(FindingOffline, "Offline since %s", alarm.LastConnection.Format(time.RFC1123))
+			}
+		}
+
+		if alarm.BatteryHealth == BatteryHealthReplace {
+			add(FindingReplaceBattery, "Battery needs replacing")
+		}
+
+		if alarm.LastManualTestTime.IsZero() {
+			add(FindingNeverTested, "Never tested manually")
+		} else if since := now.Sub(alarm.LastManualTestTime); since > opts.testInterval() {
+			add(FindingTestOverdue, "Last tested manually %d days ago", int(since.Hours()/24))
+		}
+
+		if alarm.SoftwareVersion != "" && compareVersions(alarm.SoftwareVersion, latest) < 0 {
+			add(FindingOutdatedSoftware, "Running software %s, other alarms are running %s", alarm.SoftwareVersion, latest)
+		}
+	}
+
+	return report
+}
+
+// String renders the report as text, with one line per finding
+func (r MaintenanceReport) String() string {
+	lines := []string{
+		fmt.Sprintf("Maintenance report for %s (%s): %d alarm(s), %d finding(s)", r.Name, r.StructureID, r.Alarms, len(r.Findings)),
+	}
+
+	if len(r.Findings) == 0 {
+		lines = append(lines, "No maintenance needed")
+	}
+
+	for _, f := range r.Findings {
+		lines = append(lines, fmt.Sprintf("- %s: %s", f.Name, f.Message))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// JSON renders the report as indented JSON
+func (r MaintenanceReport) JSON() ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+// compareVersions compares dotted software versions such as 3.1.4rc3, comparing the leading
+// number of each part numerically. It returns -1, 0 or 1 like strings.Compare.
+func compareVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		aPart, bPart := "", ""
+		if i < len(aParts) {
+			aPart = aParts[i]
+		}
+		if i < len(bParts) {
+			bPart = bParts[i]
+		}
+
+		aNum, aRest := splitVersionPart(aPart)
+		bNum, bRest := splitVersionPart(bPart)
+
+		if aNum != bNum {
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		}
+
+		// A part with a suffix, such as 4rc3, comes before the same part without one
+		if aRest != bRest {
+			switch {
+			case aRest == "":
+				return 1
+			case bRest == "":
+				return -1
+			}
+
+			return strings.Compare(aRest, bRest)
+		}
+	}
+
+	return 0
+}
+
+// splitVersionPart splits a version part such as 4rc3 into its leading number and the rest
+func splitVersionPart(part string) (int, string) {
+	i := 0
+	for i < len(part) && part[i] >= '0' && part[i] <= '9' {
+		i++
+	}
+
+	num, _ := strconv.Atoi(part[:i])
+
+	return num, part[i:]
+}
@@ -0,0 +1,164 @@
+package nest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceReport(t *testing.T) {
+	now := time.Now()
+
+	snapshot := Snapshot{
+		SmokeCOAlarms: map[string]SmokeCOAlarm{
+			"abc": {DeviceID: "abc", StructureID: "abc123", NameLong: "Hallway", IsOnline: true, BatteryHealth: BatteryHealthOK, SoftwareVersion: "3.1.4", LastManualTestTime: now.Add(-24 * time.Hour)},
+			"def": {DeviceID: "def", StructureID: "abc123", NameLong: "Kitchen", IsOnline: false, BatteryHealth: BatteryHealthReplace, SoftwareVersion: "3.1.4rc3", LastManualTestTime: now.Add(-45 * 24 * time.Hour)},
+			"ghi": {DeviceID: "ghi", StructureID: "abc123", NameLong: "Basement", IsOnline: true, BatteryHealth: BatteryHealthOK, SoftwareVersion: "3.1.4"},
+			"jkl": {DeviceID: "jkl", StructureID: "def456", NameLong: "Garage", IsOnline: false},
+		},
+		Structures: map[string]Structure{
+			"abc123": {StructureID: "abc123", Name: "Home", SmokeCOAlarms: []string{"abc", "def", "ghi"}},
+		},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := json.Marshal(snapshot)
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	n := Connection{
+		AccessToken: "TEST",
+		testURL:     fmt.Sprintf("%s/devices", server.URL),
+	}
+
+	t.Run("Findings", func(t *testing.T) {
+		report, err := n.MaintenanceReport("abc123", MaintenanceOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 3
+			if report.Alarms != expected {
+				t.Fatalf("Expected %d alarm(s), got %d", expected, report.Alarms)
+			}
+		}
+
+		kinds := []FindingKind{}
+		for _, f := range report.Findings {
+			kinds = append(kinds, f.Kind)
+		}
+
+		{
+			expected := []FindingKind{FindingNeverTested, FindingOffline, FindingReplaceBattery, FindingTestOverdue, FindingOutdatedSoftware}
+			if !reflect.DeepEqual(kinds, expected) {
+				t.Fatalf("Expected findings to equal %v, got %v", expected, kinds)
+			}
+		}
+
+		{
+			expected := "Last tested manually 45 days ago"
+			if report.Findings[3].Message != expected {
+				t.Fatalf("Expected message to equal %s, got %s", expected, report.Findings[3].Message)
+			}
+		}
+	})
+
+	t.Run("Test interval", func(t *testing.T) {
+		report, err := n.MaintenanceReport("abc123", MaintenanceOptions{TestInterval: 90 * 24 * time.Hour})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, f := range report.Findings {
+			if f.Kind == FindingTestOverdue {
+				t.Fatalf("Expected no overdue tests, got %+v", f)
+			}
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		report, err := n.MaintenanceReport("abc123", MaintenanceOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(report.String(), "\n")
+
+		{
+			expected := "Maintenance report for Home (abc123): 3 alarm(s), 5 finding(s)"
+			if lines[0] != expected {
+				t.Fatalf("Expected first line to equal %s, got %s", expected, lines[0])
+			}
+		}
+
+		{
+			expected := "- Basement: Never tested manually"
+			if lines[1] != expected {
+				t.Fatalf("Expected second line to equal %s, got %s", expected, lines[1])
+			}
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		report, err := n.MaintenanceReport("abc123", MaintenanceOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := report.JSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded := MaintenanceReport{}
+
+		err = json.Unmarshal(data, &decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(decoded.Findings, report.Findings) {
+			t.Fatalf("Expected findings to equal %v, got %v", report.Findings, decoded.Findings)
+		}
+	})
+
+	t.Run("Structure not found", func(t *testing.T) {
+		_, err := n.MaintenanceReport("xyz", MaintenanceOptions{})
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Structure xyz not found"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"3.1.4", "3.1.4", 0},
+		{"3.1.10", "3.1.9", 1},
+		{"3.1.4rc3", "3.1.4", -1},
+		{"2.0", "10.0", -1},
+		{"1.0", "1", 0},
+	}
+
+	for _, test := range tests {
+		if c := compareVersions(test.a, test.b); c != test.expected {
+			t.Fatalf("Expected compareVersions(%s, %s) to equal %d, got %d", test.a, test.b, test.expected, c)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package nest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// URLs used for the Works with Nest OAuth flow
+const (
+	AuthorizeURL = "https://home.nest.com/login/oauth2"
+	TokenURL     = "https://api.home.nest.com/oauth2/access_token"
+)
+
+// OAuthConfig contains the OAuth info of a Works with Nest product
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+
+	// HTTPClient is used for the token exchange and by connections created from this
+	// config, a client using http.DefaultTransport is used if nil
+	HTTPClient *http.Client
+
+	testURL string
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+type oauthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// AuthCodeURL returns the URL a user visits to authorize the product. Once they accept,
+// they are shown a PIN, or sent to the product's redirect URI with a code, which can be
+// passed to Exchange. state is sent back with the code to protect against CSRF attacks.
+func (c *OAuthConfig) AuthCodeURL(state string) string {
+	authorizeURL := AuthorizeURL
+
+	// URL to use for tests
+	if c.testURL != "" {
+		authorizeURL = fmt.Sprintf("%s/login/oauth2", c.testURL)
+	}
+
+	vals := url.Values{}
+	vals.Set("client_id", c.ClientID)
+	vals.Set("state", state)
+
+	return fmt.Sprintf("%s?%s", authorizeURL, vals.Encode())
+}
+
+// Exchange exchanges an authorization code or PIN for an access token, and returns a
+// connection that uses it
+func (c *OAuthConfig) Exchange(ctx context.Context, code string) (Connection, error) {
+	// Error checking
+	if strings.Trim(code, " ") == "" {
+		return Connection{}, errors.New("Authorization code must not be empty")
+	}
+
+	tokenURL := TokenURL
+
+	// URL to use for tests
+	if c.testURL != "" {
+		tokenURL = fmt.Sprintf("%s/oauth2/access_token", c.testURL)
+	}
+
+	vals := url.Values{}
+	vals.Set("client_id", c.ClientID)
+	vals.Set("client_secret", c.ClientSecret)
+	vals.Set("code", strings.Trim(code, " "))
+	vals.Set("grant_type", "authorization_code")
+
+	req, err := http.NewRequest("POST", tokenURL, strings.NewReader(vals.Encode()))
+	if err != nil {
+		return Connection{}, err
+	}
+	req = req.WithContext(ctx)
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := http.DefaultClient
+	if c.HTTPClient != nil {
+		client = c.HTTPClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// Report cancellation as the context's own error
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return Connection{}, ctxErr
+		}
+
+		return Connection{}, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Connection{}, err
+	}
+
+	// Check for errors
+	if resp.StatusCode != 200 {
+		apiErr := &APIError{
+			StatusCode: resp.StatusCode,
+			URL:        tokenURL,
+			Message:    string(data),
+		}
+
+		errMsg := oauthErrorResponse{}
+		if json.Unmarshal(data, &errMsg) == nil {
+			apiErr.Code = errMsg.Error
+			apiErr.Message = errMsg.ErrorDescription
+		}
+
+		return Connection{}, apiErr
+	}
+
+	token := tokenResponse{}
+
+	err = json.Unmarshal(data, &token)
+	if err != nil {
+		return Connection{}, err
+	}
+
+	if token.AccessToken == "" {
+		return Connection{}, errors.New("Access token missing from response")
+	}
+
+	n := Connection{
+		AccessToken: token.AccessToken,
+		HTTPClient:  c.HTTPClient,
+	}
+
+	if token.ExpiresIn > 0 {
+		n.TokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	return n, nil
+}
@@ -0,0 +1,118 @@
+package nest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestAuthCodeURL(t *testing.T) {
+	c := OAuthConfig{
+		ClientID: "client id",
+	}
+
+	u, err := url.Parse(c.AuthCodeURL("xyz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		expected := "https://home.nest.com/login/oauth2"
+		if actual := u.Scheme + "://" + u.Host + u.Path; actual != expected {
+			t.Fatalf("Expected URL to equal %s, got %s", expected, actual)
+		}
+	}
+
+	{
+		expected := "client id"
+		if u.Query().Get("client_id") != expected {
+			t.Fatalf("Expected client_id to equal %s, got %s", expected, u.Query().Get("client_id"))
+		}
+	}
+
+	{
+		expected := "xyz"
+		if u.Query().Get("state") != expected {
+			t.Fatalf("Expected state to equal %s, got %s", expected, u.Query().Get("state"))
+		}
+	}
+}
+
+func TestExchange(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		c, server := createTestOAuthConfig()
+		defer server.Close()
+
+		n, err := c.Exchange(context.Background(), "PIN123")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "c.TEST"
+			if n.AccessToken != expected {
+				t.Fatalf("Expected AccessToken to equal %s, got %s", expected, n.AccessToken)
+			}
+		}
+
+		{
+			expected := time.Now().Add(315360000 * time.Second)
+			if n.TokenExpiry.Sub(expected) > time.Minute || expected.Sub(n.TokenExpiry) > time.Minute {
+				t.Fatalf("Expected TokenExpiry to be about %v, got %v", expected, n.TokenExpiry)
+			}
+		}
+	})
+
+	t.Run("Empty code", func(t *testing.T) {
+		c, server := createTestOAuthConfig()
+		defer server.Close()
+
+		_, err := c.Exchange(context.Background(), " ")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Authorization code must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Invalid code", func(t *testing.T) {
+		c, server := createTestOAuthConfig()
+		defer server.Close()
+
+		_, err := c.Exchange(context.Background(), "WRONG")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected an APIError, got %v", err)
+		}
+
+		{
+			expected := "Error: authorization code not found"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Invalid client", func(t *testing.T) {
+		c, server := createTestOAuthConfig()
+		defer server.Close()
+
+		c.ClientSecret = "wrong"
+
+		_, err := c.Exchange(context.Background(), "PIN123")
+		if !errors.Is(err, ErrUnauthorized) {
+			t.Fatalf("Expected error to equal %v, got %v", ErrUnauthorized, err)
+		}
+	})
+}
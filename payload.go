@@ -0,0 +1,99 @@
+package nest
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// thermostatRequest is the body written to change a thermostat. Only the fields that are
+// set are sent.
+type thermostatRequest struct {
+	HVACMode               HVACMode         `json:"hvac_mode,omitempty"`
+	TargetTemperatureF     *int             `json:"target_temperature_f,omitempty"`
+	TargetTemperatureC     *float64         `json:"target_temperature_c,omitempty"`
+	TargetTemperatureHighF *int             `json:"target_temperature_high_f,omitempty"`
+	TargetTemperatureHighC *float64         `json:"target_temperature_high_c,omitempty"`
+	TargetTemperatureLowF  *int             `json:"target_temperature_low_f,omitempty"`
+	TargetTemperatureLowC  *float64         `json:"target_temperature_low_c,omitempty"`
+	FanTimerActive         *bool            `json:"fan_timer_active,omitempty"`
+	FanTimerDuration       int              `json:"fan_timer_duration,omitempty"`
+	Label                  string           `json:"label,omitempty"`
+	TemperatureScale       TemperatureScale `json:"temperature_scale,omitempty"`
+}
+
+// setTargetTemperature sets the target temperature field for the scale of temp
+func (r *thermostatRequest) setTargetTemperature(temp Temperature) {
+	r.TargetTemperatureF, r.TargetTemperatureC = temperatureFields(temp)
+}
+
+// setTargetRange sets the target low and high temperature fields for the scale of high
+func (r *thermostatRequest) setTargetRange(low, high Temperature) {
+	r.TargetTemperatureLowF, r.TargetTemperatureLowC = temperatureFields(low.In(high.Scale))
+	r.TargetTemperatureHighF, r.TargetTemperatureHighC = temperatureFields(high)
+}
+
+// setFanTimer turns the fan timer on for duration minutes, or off if duration is 0
+func (r *thermostatRequest) setFanTimer(duration int) {
+	active := duration != 0
+	r.FanTimerActive = &active
+	r.FanTimerDuration = duration
+}
+
+// temperatureFields returns the rounded value of temp for the field of its scale, leaving
+// the other scale's field unset
+func temperatureFields(temp Temperature) (*int, *float64) {
+	if temp.Scale == TemperatureScaleF {
+		f := int(temp.InFahrenheit())
+		return &f, nil
+	}
+
+	c := temp.InCelsius()
+	return nil, &c
+}
+
+// temperatureFromFields returns the temperature of whichever scale's field is set
+func temperatureFromFields(f *int, c *float64) Temperature {
+	if f != nil {
+		return Fahrenheit(float64(*f))
+	}
+
+	if c != nil {
+		return Celsius(*c)
+	}
+
+	return Temperature{}
+}
+
+// cameraRequest is the body written to change a camera
+type cameraRequest struct {
+	IsStreaming bool `json:"is_streaming"`
+}
+
+// structureRequest is the body written to change a structure
+type structureRequest struct {
+	Away AwayState `json:"away"`
+}
+
+// etaRequest is the body written to a structure's eta
+type etaRequest struct {
+	TripID                      string   `json:"trip_id"`
+	EstimatedArrivalWindowBegin etaTime  `json:"estimated_arrival_window_begin"`
+	EstimatedArrivalWindowEnd   *etaTime `json:"estimated_arrival_window_end,omitempty"`
+}
+
+// etaResponse is the window Nest applied to a structure's eta
+type etaResponse struct {
+	EstimatedArrivalWindowBegin timestamp `json:"estimated_arrival_window_begin"`
+	EstimatedArrivalWindowEnd   timestamp `json:"estimated_arrival_window_end"`
+}
+
+// etaTime is a time in an ETA, the zero time is written as 0 which cancels the ETA
+type etaTime time.Time
+
+func (t etaTime) MarshalJSON() ([]byte, error) {
+	if time.Time(t).IsZero() {
+		return []byte("0"), nil
+	}
+
+	return json.Marshal(time.Time(t).UTC().Format(timeFormat))
+}
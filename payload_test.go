@@ -0,0 +1,120 @@
+package nest
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestWritePayloads(t *testing.T) {
+	heatF := Thermostat{DeviceID: "abc", TemperatureScale: TemperatureScaleF, HVACMode: HVACModeHeat, CanHeat: true, CanCool: true, HasFan: true}
+
+	heatC := heatF
+	heatC.TemperatureScale = TemperatureScaleC
+
+	heatCoolF := heatF
+	heatCoolF.HVACMode = HVACModeHeatCool
+
+	heatCoolC := heatC
+	heatCoolC.HVACMode = HVACModeHeatCool
+
+	begin := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	end := begin.Add(30 * time.Minute)
+
+	tests := []struct {
+		name       string
+		thermostat Thermostat
+		set        func(n *Connection) error
+		path       string
+		expected   string
+	}{
+		{"SetTemperatureScale", heatF, func(n *Connection) error {
+			_, err := n.SetTemperatureScale("abc", TemperatureScaleC)
+			return err
+		}, "/devices/thermostats/abc", `{"temperature_scale":"C"}`},
+		{"SetTargetTemperatureF", heatF, func(n *Connection) error {
+			_, err := n.SetTargetTemperatureF("abc", 70)
+			return err
+		}, "/devices/thermostats/abc", `{"target_temperature_f":70}`},
+		{"SetTargetTemperatureC", heatC, func(n *Connection) error {
+			_, err := n.SetTargetTemperatureC("abc", 21.5)
+			return err
+		}, "/devices/thermostats/abc", `{"target_temperature_c":21.5}`},
+		{"SetTargetHighLowTemperatureF", heatCoolF, func(n *Connection) error {
+			_, _, err := n.SetTargetHighLowTemperatureF("abc", 74, 70)
+			return err
+		}, "/devices/thermostats/abc", `{"target_temperature_high_f":74,"target_temperature_low_f":70}`},
+		{"SetTargetHighLowTemperatureC", heatCoolC, func(n *Connection) error {
+			_, _, err := n.SetTargetHighLowTemperatureC("abc", 23.5, 21)
+			return err
+		}, "/devices/thermostats/abc", `{"target_temperature_high_c":23.5,"target_temperature_low_c":21}`},
+		{"SetTargetTemperature", heatC, func(n *Connection) error {
+			_, err := n.SetTargetTemperature("abc", Fahrenheit(70))
+			return err
+		}, "/devices/thermostats/abc", `{"target_temperature_f":70}`},
+		{"SetTargetRange", heatCoolF, func(n *Connection) error {
+			_, _, err := n.SetTargetRange("abc", Celsius(21), Fahrenheit(75))
+			return err
+		}, "/devices/thermostats/abc", `{"target_temperature_high_f":75,"target_temperature_low_f":70}`},
+		{"SetHVACMode", heatF, func(n *Connection) error {
+			_, err := n.SetHVACMode("abc", HVACModeHeatCool)
+			return err
+		}, "/devices/thermostats/abc", `{"hvac_mode":"heat-cool"}`},
+		{"SetThermostatLabel", heatF, func(n *Connection) error {
+			_, err := n.SetThermostatLabel("abc", `Kid's "Room"`)
+			return err
+		}, "/devices/thermostats/abc", `{"label":"Kid's \"Room\""}`},
+		{"TurnOnFanTimer", heatF, func(n *Connection) error {
+			_, err := n.TurnOnFanTimer("abc", 15)
+			return err
+		}, "/devices/thermostats/abc", `{"fan_timer_active":true,"fan_timer_duration":15}`},
+		{"TurnOffFanTimer", heatF, func(n *Connection) error {
+			_, err := n.TurnOffFanTimer("abc")
+			return err
+		}, "/devices/thermostats/abc", `{"fan_timer_active":false}`},
+		{"UpdateThermostat", heatF, func(n *Connection) error {
+			_, err := n.UpdateThermostat("abc", NewThermostatUpdate().HVACMode(HVACModeCool).TargetTemperature(Celsius(24)).Label("Hall"))
+			return err
+		}, "/devices/thermostats/abc", `{"hvac_mode":"cool","target_temperature_c":24,"label":"Hall"}`},
+		{"TurnOnStreaming", heatF, func(n *Connection) error {
+			_, err := n.TurnOnStreaming("abc")
+			return err
+		}, "/devices/cameras/abc", `{"is_streaming":true}`},
+		{"TurnOffStreaming", heatF, func(n *Connection) error {
+			_, err := n.TurnOffStreaming("abc")
+			return err
+		}, "/devices/cameras/abc", `{"is_streaming":false}`},
+		{"SetStructureAway", heatF, func(n *Connection) error {
+			_, err := n.SetStructureAway("abc", AwayStateAway)
+			return err
+		}, "/devices/structures/abc", `{"away":"away"}`},
+		{"SetStructureETA", heatF, func(n *Connection) error {
+			_, _, err := n.SetStructureETA("abc", "trip1", begin, end)
+			return err
+		}, "/devices/structures/abc/eta", `{"trip_id":"trip1","estimated_arrival_window_begin":"2017-01-01T12:00:00.000Z","estimated_arrival_window_end":"2017-01-01T12:30:00.000Z"}`},
+		{"CancelStructureETA", heatF, func(n *Connection) error { return n.CancelStructureETA("abc", "trip1") }, "/devices/structures/abc/eta", `{"trip_id":"trip1","estimated_arrival_window_begin":0}`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			n, server, write := createTestWriteConnection(func(url string) []byte {
+				data, _ := json.Marshal(test.thermostat)
+				return data
+			})
+			defer server.Close()
+
+			err := test.set(&n)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if write.path != test.path {
+				t.Fatalf("Expected path to equal %s, got %s", test.path, write.path)
+			}
+
+			if write.body != test.expected {
+				t.Fatalf("Expected body to equal %s, got %s", test.expected, write.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package nest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrWriteLimited is returned by a fail-fast WriteLimiter when a write would exceed its limits
+var ErrWriteLimited = errors.New("Write rate limit exceeded")
+
+// WriteLimiter is a token bucket limiter for writes, with one bucket per device and
+// one per structure. Nest blocks access tokens that write too often, so the defaults
+// are deliberately conservative. A WriteLimiter is safe for concurrent use and can be
+// shared by connections using the same access token.
+type WriteLimiter struct {
+	// DeviceBurst is the number of writes a device can receive at once, defaults to 3
+	DeviceBurst int
+
+	// DeviceInterval is the time it takes a device to regain a write, defaults to 1 minute
+	DeviceInterval time.Duration
+
+	// StructureBurst is the number of writes the devices in a structure can receive at
+	// once, defaults to 10
+	StructureBurst int
+
+	// StructureInterval is the time it takes a structure to regain a write, defaults to 20 seconds
+	StructureInterval time.Duration
+
+	// FailFast returns ErrWriteLimited instead of waiting when a write would exceed a limit
+	FailFast bool
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+
+	// Structure each device belongs to, looked up the first time the device is written to
+	structures map[string]string
+}
+
+type tokenBucket struct {
+	tokens   float64
+	burst    float64
+	interval time.Duration
+	last     time.Time
+}
+
+// refill adds the tokens regained since the bucket was last used
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += float64(now.Sub(b.last)) / float64(b.interval)
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// wait returns how long until the bucket has a token
+func (b *tokenBucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) * float64(b.interval))
+}
+
+func (l *WriteLimiter) bucket(key string, burst int, interval time.Duration, now time.Time) *tokenBucket {
+	if l.buckets == nil {
+		l.buckets = make(map[string]*tokenBucket)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{
+			tokens:   float64(burst),
+			burst:    float64(burst),
+			interval: interval,
+			last:     now,
+		}
+		l.buckets[key] = b
+	}
+
+	return b
+}
+
+// wait takes a token from the device's and structure's buckets, waiting until both have
+// one unless the limiter fails fast. Writes to a structure itself have an empty deviceKey
+// and only use the structure's bucket.
+func (l *WriteLimiter) wait(ctx context.Context, deviceKey, structureID string) error {
+	deviceBurst, deviceInterval := l.DeviceBurst, l.DeviceInterval
+	if deviceBurst <= 0 {
+		deviceBurst = 3
+	}
+	if deviceInterval <= 0 {
+		deviceInterval = time.Minute
+	}
+
+	structureBurst, structureInterval := l.StructureBurst, l.StructureInterval
+	if structureBurst <= 0 {
+		structureBurst = 10
+	}
+	if structureInterval <= 0 {
+		structureInterval = 20 * time.Second
+	}
+
+	for {
+		l.mu.Lock()
+
+		now := time.Now()
+		buckets := []*tokenBucket{}
+		if deviceKey != "" {
+			buckets = append(buckets, l.bucket("device:"+deviceKey, deviceBurst, deviceInterval, now))
+		}
+		if structureID != "" {
+			buckets = append(buckets, l.bucket("structure:"+structureID, structureBurst, structureInterval, now))
+		}
+
+		wait := time.Duration(0)
+		for _, b := range buckets {
+			b.refill(now)
+			if w := b.wait(); w > wait {
+				wait = w
+			}
+		}
+
+		if wait == 0 {
+			for _, b := range buckets {
+				b.tokens--
+			}
+		}
+
+		l.mu.Unlock()
+
+		if wait == 0 {
+			return nil
+		}
+
+		if l.FailFast {
+			return ErrWriteLimited
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// limitWrite waits until the device can be written to without exceeding the connection's
+// write limits, if it has any
+func (n *Connection) limitWrite(deviceType, deviceID string) error {
+	l := n.Limiter
+	if l == nil {
+		return nil
+	}
+
+	// Writes to a structure only count against the structure's limit
+	if deviceType == "structures" {
+		return l.wait(n.Context(), "", deviceID)
+	}
+
+	deviceKey := deviceType + "/" + deviceID
+
+	l.mu.Lock()
+	structureID, ok := l.structures[deviceKey]
+	l.mu.Unlock()
+
+	if !ok {
+		err := n.getValue(deviceType, deviceID, "structure_id", &structureID)
+		if err != nil {
+			return err
+		}
+
+		l.mu.Lock()
+		if l.structures == nil {
+			l.structures = make(map[string]string)
+		}
+		l.structures[deviceKey] = structureID
+		l.mu.Unlock()
+	}
+
+	return l.wait(n.Context(), deviceKey, structureID)
+}
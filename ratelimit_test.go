@@ -0,0 +1,148 @@
+package nest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWriteLimiter(t *testing.T) {
+	t.Run("Device limit", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		n.Limiter = &WriteLimiter{
+			DeviceBurst:    1,
+			DeviceInterval: time.Hour,
+			FailFast:       true,
+		}
+
+		_, err := n.TurnOnStreaming("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = n.TurnOffStreaming("abc")
+		if !errors.Is(err, ErrWriteLimited) {
+			t.Fatalf("Expected error to equal %v, got %v", ErrWriteLimited, err)
+		}
+
+		// Other devices have their own limit
+		_, err = n.SetHVACMode("abc", "heat")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Structure limit", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		n.Limiter = &WriteLimiter{
+			StructureBurst:    1,
+			StructureInterval: time.Hour,
+			FailFast:          true,
+		}
+
+		_, err := n.SetHVACMode("abc", "heat")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Both thermostats are in structure abc123
+		_, err = n.SetHVACMode("def", "heat")
+		if !errors.Is(err, ErrWriteLimited) {
+			t.Fatalf("Expected error to equal %v, got %v", ErrWriteLimited, err)
+		}
+	})
+
+	t.Run("Structure writes", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		n.Limiter = &WriteLimiter{
+			DeviceBurst:    1,
+			DeviceInterval: time.Hour,
+			StructureBurst: 3,
+			FailFast:       true,
+		}
+
+		// Structures aren't limited by the device limit
+		for i := 0; i < 3; i++ {
+			_, err := n.SetStructureAway("abc", AwayStateAway)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		_, err := n.SetStructureAway("abc", AwayStateAway)
+		if !errors.Is(err, ErrWriteLimited) {
+			t.Fatalf("Expected error to equal %v, got %v", ErrWriteLimited, err)
+		}
+
+		// A structure's own writes don't use a device-sized bucket for its devices
+		n.Limiter = &WriteLimiter{FailFast: true}
+
+		for i := 0; i < 3; i++ {
+			_, err := n.SetStructureAway("abc123", AwayStateAway)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		_, err = n.SetHVACMode("abc", "heat")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Blocking", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		n.Limiter = &WriteLimiter{
+			DeviceBurst:    1,
+			DeviceInterval: 50 * time.Millisecond,
+		}
+
+		start := time.Now()
+
+		for i := 0; i < 2; i++ {
+			_, err := n.TurnOnStreaming("abc")
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		{
+			expected := 40 * time.Millisecond
+			if elapsed := time.Since(start); elapsed < expected {
+				t.Fatalf("Expected writes to take at least %v, took %v", expected, elapsed)
+			}
+		}
+	})
+
+	t.Run("Context cancelled while waiting", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		n.Limiter = &WriteLimiter{
+			DeviceBurst:    1,
+			DeviceInterval: time.Hour,
+		}
+
+		_, err := n.TurnOnStreaming("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = n.WithContext(ctx).TurnOnStreaming("abc")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Expected error to equal %v, got %v", context.DeadlineExceeded, err)
+		}
+	})
+}
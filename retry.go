@@ -0,0 +1,87 @@
+package nest
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Requests are retried when the
+// API is rate limiting or has a server error, or the request couldn't be sent at all.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts made for a request, including the first
+	MaxAttempts int
+
+	// MinBackoff is the wait before the first retry, it doubles with each retry after
+	// that. Defaults to 1 second.
+	MinBackoff time.Duration
+
+	// MaxBackoff limits the wait between retries, defaults to 30 seconds. A request isn't
+	// retried if the API asks to wait longer than this with a Retry-After header.
+	MaxBackoff time.Duration
+
+	// RetryWrites allows PUT requests to be retried, only GET requests are retried otherwise
+	RetryWrites bool
+
+	// OnRetry is called before each retry when set, e.g. for logging or metrics
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a failed attempt that is about to be retried
+type RetryEvent struct {
+	// Attempt is the number of the attempt that failed, starting at 1
+	Attempt int
+	Method  string
+	URL     string
+	Err     error
+
+	// Wait is how long until the next attempt
+	Wait time.Duration
+}
+
+// shouldRetry reports whether a request that failed with err should be tried again,
+// and how long to wait first
+func (p *RetryPolicy) shouldRetry(method string, attempt int, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if method != "GET" && !(method == "PUT" && p.RetryWrites) {
+		return 0, false
+	}
+
+	minBackoff := p.MinBackoff
+	if minBackoff <= 0 {
+		minBackoff = time.Second
+	}
+
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+
+	// Cancelled requests are never retried
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if !errors.Is(apiErr, ErrRateLimited) && apiErr.StatusCode < 500 {
+			return 0, false
+		}
+
+		if apiErr.RetryAfter > 0 {
+			return apiErr.RetryAfter, apiErr.RetryAfter <= maxBackoff
+		}
+	}
+
+	// Exponential backoff with jitter, between half and all of the backoff
+	backoff := minBackoff << uint(attempt-1)
+	if backoff > maxBackoff || backoff <= 0 {
+		backoff = maxBackoff
+	}
+
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1)), true
+}
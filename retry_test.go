@@ -0,0 +1,200 @@
+package nest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// createTestRetryConnection creates a connection to a server that fails with statusCode
+// the given number of times before succeeding
+func createTestRetryConnection(failures, statusCode int, retryAfter string) (Connection, *httptest.Server, *[]string) {
+	bodies := []string{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if len(bodies) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(statusCode)
+			w.Write([]byte(`{"error":"blocked","message":"blocked"}`))
+			return
+		}
+
+		w.Write(body)
+	}))
+
+	return Connection{
+		AccessToken: "TEST",
+		testURL:     fmt.Sprintf("%s/devices", server.URL),
+		Retry: &RetryPolicy{
+			MaxAttempts: 3,
+			MinBackoff:  time.Millisecond,
+			MaxBackoff:  10 * time.Millisecond,
+		},
+	}, server, &bodies
+}
+
+func TestRetryPolicy(t *testing.T) {
+	t.Run("GET retried", func(t *testing.T) {
+		n, server, bodies := createTestRetryConnection(2, 429, "")
+		defer server.Close()
+
+		retries := []RetryEvent{}
+		n.Retry.OnRetry = func(e RetryEvent) {
+			retries = append(retries, e)
+		}
+
+		_, err := n.GetThermostats()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 3
+			if len(*bodies) != expected {
+				t.Fatalf("Expected %d request(s), got %d", expected, len(*bodies))
+			}
+		}
+
+		{
+			expected := 2
+			if len(retries) != expected {
+				t.Fatalf("Expected %d retry event(s), got %d", expected, len(retries))
+			}
+
+			if retries[1].Attempt != expected {
+				t.Fatalf("Expected Attempt to equal %d, got %d", expected, retries[1].Attempt)
+			}
+		}
+	})
+
+	t.Run("Max attempts reached", func(t *testing.T) {
+		n, server, bodies := createTestRetryConnection(5, 503, "")
+		defer server.Close()
+
+		_, err := n.GetThermostats()
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := 3
+			if len(*bodies) != expected {
+				t.Fatalf("Expected %d request(s), got %d", expected, len(*bodies))
+			}
+		}
+	})
+
+	t.Run("Validation errors not retried", func(t *testing.T) {
+		n, server, bodies := createTestRetryConnection(1, 400, "")
+		defer server.Close()
+
+		// Respond with a validation error rather than blocked
+		server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*bodies = append(*bodies, "")
+			w.WriteHeader(400)
+			w.Write([]byte(`{"error":"Invalid value","message":"Invalid value"}`))
+		})
+
+		_, err := n.GetThermostats()
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := 1
+			if len(*bodies) != expected {
+				t.Fatalf("Expected %d request(s), got %d", expected, len(*bodies))
+			}
+		}
+	})
+
+	t.Run("PUT not retried by default", func(t *testing.T) {
+		n, server, bodies := createTestRetryConnection(1, 429, "")
+		defer server.Close()
+
+		_, err := n.TurnOnStreaming("abc")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := 1
+			if len(*bodies) != expected {
+				t.Fatalf("Expected %d request(s), got %d", expected, len(*bodies))
+			}
+		}
+	})
+
+	t.Run("PUT retried", func(t *testing.T) {
+		n, server, bodies := createTestRetryConnection(1, 429, "")
+		defer server.Close()
+
+		n.Retry.RetryWrites = true
+
+		_, err := n.TurnOnStreaming("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 2
+			if len(*bodies) != expected {
+				t.Fatalf("Expected %d request(s), got %d", expected, len(*bodies))
+			}
+		}
+
+		// The body is sent again with the retry
+		if (*bodies)[0] == "" || (*bodies)[0] != (*bodies)[1] {
+			t.Fatalf("Expected both request bodies to match, got %s and %s", (*bodies)[0], (*bodies)[1])
+		}
+	})
+
+	t.Run("Retry-After honoured", func(t *testing.T) {
+		n, server, _ := createTestRetryConnection(1, 429, "1")
+		defer server.Close()
+
+		n.Retry.MaxBackoff = 2 * time.Second
+
+		wait := time.Duration(0)
+		n.Retry.OnRetry = func(e RetryEvent) {
+			wait = e.Wait
+		}
+
+		_, err := n.GetThermostats()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := time.Second
+			if wait != expected {
+				t.Fatalf("Expected Wait to equal %v, got %v", expected, wait)
+			}
+		}
+	})
+
+	t.Run("Retry-After longer than max backoff", func(t *testing.T) {
+		n, server, bodies := createTestRetryConnection(1, 429, "3600")
+		defer server.Close()
+
+		_, err := n.GetThermostats()
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := 1
+			if len(*bodies) != expected {
+				t.Fatalf("Expected %d request(s), got %d", expected, len(*bodies))
+			}
+		}
+	})
+}
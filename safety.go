@@ -0,0 +1,214 @@
+package nest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// AlarmKind is the kind of hazard an alarm is for
+type AlarmKind string
+
+// Kinds of alarm
+const (
+	AlarmKindSmoke AlarmKind = "smoke"
+	AlarmKindCO    AlarmKind = "co"
+)
+
+// AlarmTransition is a change in the state of a smoke or CO alarm. DeviceID is empty for
+// a change in a structure's roll-up of the alarms in it.
+type AlarmTransition struct {
+	Kind        AlarmKind
+	StructureID string
+	DeviceID    string
+	Name        string
+
+	// From is empty the first time the alarm is seen
+	From AlarmState
+	To   AlarmState
+	Time time.Time
+}
+
+// ActiveAlarm is a smoke or CO alarm, or a structure's roll-up, in the warning or
+// emergency state
+type ActiveAlarm struct {
+	Kind        AlarmKind
+	StructureID string
+	DeviceID    string
+	Name        string
+	State       AlarmState
+
+	// Since is when the alarm entered its state
+	Since time.Time
+}
+
+type alarmKey struct {
+	structureID string
+	deviceID    string
+	kind        AlarmKind
+}
+
+// SafetyMonitor watches the smoke and CO states of smoke/co alarms and structures, calling
+// its handlers whenever one changes. Repeated states are ignored, so alarms and structures
+// can be observed as often as they're received. The zero value is ready to use.
+type SafetyMonitor struct {
+	mu       sync.Mutex
+	handlers []func(AlarmTransition)
+	states   map[alarmKey]ActiveAlarm
+}
+
+// OnTransition registers handler to be called with every change of alarm state, including
+// the first state seen for an alarm unless it's OK. Handlers are called in the order they
+// were registered, from the goroutine that observed the change.
+func (m *SafetyMonitor) OnTransition(handler func(AlarmTransition)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handlers = append(m.handlers, handler)
+}
+
+// ObserveSmokeCOAlarm records the smoke and CO states of alarm
+func (m *SafetyMonitor) ObserveSmokeCOAlarm(alarm SmokeCOAlarm) {
+	m.observe(
+		ActiveAlarm{Kind: AlarmKindSmoke, StructureID: alarm.StructureID, DeviceID: alarm.DeviceID, Name: alarm.NameLong, State: alarm.SmokeAlarmState},
+		ActiveAlarm{Kind: AlarmKindCO, StructureID: alarm.StructureID, DeviceID: alarm.DeviceID, Name: alarm.NameLong, State: alarm.COAlarmState},
+	)
+}
+
+// ObserveStructure records the smoke and CO states the structure rolls up from its alarms
+func (m *SafetyMonitor) ObserveStructure(structure Structure) {
+	m.observe(
+		ActiveAlarm{Kind: AlarmKindSmoke, StructureID: structure.StructureID, Name: structure.Name, State: structure.SmokeAlarmState},
+		ActiveAlarm{Kind: AlarmKindCO, StructureID: structure.StructureID, Name: structure.Name, State: structure.COAlarmState},
+	)
+}
+
+// ObserveSnapshot records the states of every alarm and structure in the snapshot
+func (m *SafetyMonitor) ObserveSnapshot(snapshot Snapshot) {
+	for _, alarm := range snapshot.SmokeCOAlarms {
+		m.ObserveSmokeCOAlarm(alarm)
+	}
+
+	for _, structure := range snapshot.Structures {
+		m.ObserveStructure(structure)
+	}
+}
+
+// Refresh fetches every alarm and structure with a single request and records their states
+func (m *SafetyMonitor) Refresh(n *Connection) error {
+	snapshot, err := n.GetAll()
+	if err != nil {
+		return err
+	}
+
+	m.ObserveSnapshot(snapshot)
+
+	return nil
+}
+
+// Watch records every alarm and structure received on the channels until both are closed
+// or ctx is cancelled. The channels are normally those of a StreamFanout subscription to
+// SmokeCOAlarms and Structures, since a Stream's other channels would be left unread.
+func (m *SafetyMonitor) Watch(ctx context.Context, alarms <-chan SmokeCOAlarm, structures <-chan Structure) error {
+	for alarms != nil || structures != nil {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case alarm, ok := <-alarms:
+			if !ok {
+				alarms = nil
+				continue
+			}
+
+			m.ObserveSmokeCOAlarm(alarm)
+		case structure, ok := <-structures:
+			if !ok {
+				structures = nil
+				continue
+			}
+
+			m.ObserveStructure(structure)
+		}
+	}
+
+	return nil
+}
+
+// ActiveAlarms returns the alarms and structures that are currently in the warning or
+// emergency state, ordered by structure, then device
+func (m *SafetyMonitor) ActiveAlarms() []ActiveAlarm {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	active := []ActiveAlarm{}
+	for _, alarm := range m.states {
+		if alarm.State == AlarmStateWarning || alarm.State == AlarmStateEmergency {
+			active = append(active, alarm)
+		}
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		a, b := active[i], active[j]
+		if a.StructureID != b.StructureID {
+			return a.StructureID < b.StructureID
+		}
+		if a.DeviceID != b.DeviceID {
+			return a.DeviceID < b.DeviceID
+		}
+		return a.Kind > b.Kind
+	})
+
+	return active
+}
+
+// observe records the states of alarms and calls the handlers with the ones that changed
+func (m *SafetyMonitor) observe(alarms ...ActiveAlarm) {
+	now := time.Now()
+	transitions := []AlarmTransition{}
+
+	m.mu.Lock()
+	if m.states == nil {
+		m.states = make(map[alarmKey]ActiveAlarm)
+	}
+
+	for _, alarm := range alarms {
+		// Devices that don't report a state, such as alarms without a CO sensor, are skipped
+		if alarm.State == "" {
+			continue
+		}
+
+		key := alarmKey{structureID: alarm.StructureID, deviceID: alarm.DeviceID, kind: alarm.Kind}
+
+		last, seen := m.states[key]
+		if seen && last.State == alarm.State {
+			continue
+		}
+
+		alarm.Since = now
+		m.states[key] = alarm
+
+		if !seen && alarm.State == AlarmStateOK {
+			continue
+		}
+
+		transitions = append(transitions, AlarmTransition{
+			Kind:        alarm.Kind,
+			StructureID: alarm.StructureID,
+			DeviceID:    alarm.DeviceID,
+			Name:        alarm.Name,
+			From:        last.State,
+			To:          alarm.State,
+			Time:        now,
+		})
+	}
+
+	handlers := m.handlers
+	m.mu.Unlock()
+
+	for _, t := range transitions {
+		for _, handler := range handlers {
+			handler(t)
+		}
+	}
+}
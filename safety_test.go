@@ -0,0 +1,146 @@
+package nest
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSafetyMonitor(t *testing.T) {
+	t.Run("Transitions", func(t *testing.T) {
+		m := &SafetyMonitor{}
+
+		transitions := []AlarmTransition{}
+		m.OnTransition(func(tr AlarmTransition) {
+			transitions = append(transitions, tr)
+		})
+
+		states := []AlarmState{AlarmStateOK, AlarmStateOK, AlarmStateWarning, AlarmStateWarning, AlarmStateEmergency, AlarmStateOK}
+		for _, state := range states {
+			m.ObserveSmokeCOAlarm(SmokeCOAlarm{DeviceID: "abc", StructureID: "abc123", SmokeAlarmState: state, COAlarmState: AlarmStateOK})
+		}
+
+		{
+			expected := 3
+			if len(transitions) != expected {
+				t.Fatalf("Expected %d transition(s), got %d", expected, len(transitions))
+			}
+		}
+
+		{
+			expected := AlarmTransition{Kind: AlarmKindSmoke, StructureID: "abc123", DeviceID: "abc", From: AlarmStateWarning, To: AlarmStateEmergency}
+			tr := transitions[1]
+			if tr.Kind != expected.Kind || tr.DeviceID != expected.DeviceID || tr.From != expected.From || tr.To != expected.To {
+				t.Fatalf("Expected transition to equal %+v, got %+v", expected, tr)
+			}
+		}
+	})
+
+	t.Run("First state seen", func(t *testing.T) {
+		m := &SafetyMonitor{}
+
+		transitions := []AlarmTransition{}
+		m.OnTransition(func(tr AlarmTransition) {
+			transitions = append(transitions, tr)
+		})
+
+		m.ObserveStructure(Structure{StructureID: "abc123", SmokeAlarmState: AlarmStateOK, COAlarmState: AlarmStateWarning})
+
+		{
+			expected := 1
+			if len(transitions) != expected {
+				t.Fatalf("Expected %d transition(s), got %d", expected, len(transitions))
+			}
+		}
+
+		{
+			expected := AlarmState("")
+			if transitions[0].From != expected || transitions[0].Kind != AlarmKindCO {
+				t.Fatalf("Expected a CO transition from %q, got %+v", expected, transitions[0])
+			}
+		}
+	})
+
+	t.Run("Active alarms", func(t *testing.T) {
+		m := &SafetyMonitor{}
+
+		m.ObserveSnapshot(Snapshot{
+			SmokeCOAlarms: map[string]SmokeCOAlarm{
+				"abc": {DeviceID: "abc", StructureID: "abc123", SmokeAlarmState: AlarmStateEmergency, COAlarmState: AlarmStateOK},
+				"def": {DeviceID: "def", StructureID: "def456", SmokeAlarmState: AlarmStateOK, COAlarmState: AlarmStateOK},
+			},
+			Structures: map[string]Structure{
+				"abc123": {StructureID: "abc123", SmokeAlarmState: AlarmStateEmergency, COAlarmState: AlarmStateOK},
+				"def456": {StructureID: "def456", SmokeAlarmState: AlarmStateOK, COAlarmState: AlarmStateOK},
+			},
+		})
+
+		active := m.ActiveAlarms()
+
+		{
+			expected := 2
+			if len(active) != expected {
+				t.Fatalf("Expected %d active alarm(s), got %d", expected, len(active))
+			}
+		}
+
+		// The structure's roll-up comes before its devices
+		{
+			expected := "abc"
+			if active[0].DeviceID != "" || active[1].DeviceID != expected {
+				t.Fatalf("Expected the structure then device %s, got %+v", expected, active)
+			}
+		}
+
+		m.ObserveSmokeCOAlarm(SmokeCOAlarm{DeviceID: "abc", StructureID: "abc123", SmokeAlarmState: AlarmStateOK, COAlarmState: AlarmStateOK})
+
+		{
+			expected := 1
+			if len(m.ActiveAlarms()) != expected {
+				t.Fatalf("Expected %d active alarm(s), got %d", expected, len(m.ActiveAlarms()))
+			}
+		}
+	})
+
+	t.Run("Refresh", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		m := &SafetyMonitor{}
+
+		err := m.Refresh(&n)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 0
+			if len(m.ActiveAlarms()) != expected {
+				t.Fatalf("Expected %d active alarm(s), got %d", expected, len(m.ActiveAlarms()))
+			}
+		}
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		m := &SafetyMonitor{}
+
+		alarms := make(chan SmokeCOAlarm, 1)
+		structures := make(chan Structure, 1)
+
+		alarms <- SmokeCOAlarm{DeviceID: "abc", StructureID: "abc123", SmokeAlarmState: AlarmStateOK, COAlarmState: AlarmStateWarning}
+		structures <- Structure{StructureID: "abc123", SmokeAlarmState: AlarmStateOK, COAlarmState: AlarmStateWarning}
+		close(alarms)
+		close(structures)
+
+		err := m.Watch(context.Background(), alarms, structures)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 2
+			if len(m.ActiveAlarms()) != expected {
+				t.Fatalf("Expected %d active alarm(s), got %d", expected, len(m.ActiveAlarms()))
+			}
+		}
+	})
+}
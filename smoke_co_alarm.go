@@ -5,26 +5,50 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // SmokeCOAlarm contains all the data for an individual Nest smoke/co alarm
 type SmokeCOAlarm struct {
-	DeviceID           string `json:"device_id"`
-	Locale             string `json:"locale"`
-	SoftwareVersion    string `json:"software_version"`
-	StructureID        string `json:"structure_id"`
-	Name               string `json:"name"`
-	NameLong           string `json:"name_long"`
-	LastConnection     string `json:"last_connection"`
-	IsOnline           bool   `json:"is_online"`
-	BatteryHealth      string `json:"battery_health"`
-	COAlarmState       string `json:"co_alarm_state"`
-	SmokeAlarmState    string `json:"smoke_alarm_state"`
-	IsManualTestActive bool   `json:"is_manual_test_active"`
-	LastManualTestTime string `json:"last_manual_test_time"`
-	UIColorState       string `json:"ui_color_state"`
-	WhereID            string `json:"where_id"`
-	WhereName          string `json:"where_name"`
+	DeviceID           string        `json:"device_id"`
+	Locale             string        `json:"locale"`
+	SoftwareVersion    string        `json:"software_version"`
+	StructureID        string        `json:"structure_id"`
+	Name               string        `json:"name"`
+	NameLong           string        `json:"name_long"`
+	LastConnection     time.Time     `json:"last_connection"`
+	IsOnline           bool          `json:"is_online"`
+	BatteryHealth      BatteryHealth `json:"battery_health"`
+	COAlarmState       AlarmState    `json:"co_alarm_state"`
+	SmokeAlarmState    AlarmState    `json:"smoke_alarm_state"`
+	IsManualTestActive bool          `json:"is_manual_test_active"`
+	LastManualTestTime time.Time     `json:"last_manual_test_time"`
+	UIColorState       UIColorState  `json:"ui_color_state"`
+	WhereID            string        `json:"where_id"`
+	WhereName          string        `json:"where_name"`
+}
+
+// UnmarshalJSON decodes a smoke/co alarm, leaving timestamps that are empty as the zero time
+func (s *SmokeCOAlarm) UnmarshalJSON(data []byte) error {
+	type smokeCOAlarm SmokeCOAlarm
+
+	aux := struct {
+		*smokeCOAlarm
+		LastConnection     timestamp `json:"last_connection"`
+		LastManualTestTime timestamp `json:"last_manual_test_time"`
+	}{
+		smokeCOAlarm: (*smokeCOAlarm)(s),
+	}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	s.LastConnection = aux.LastConnection.Time
+	s.LastManualTestTime = aux.LastManualTestTime.Time
+
+	return nil
 }
 
 // GetSmokeCOAlarms returns all Nest smoke/co alarms along with all their data
@@ -101,40 +125,64 @@ func (n *Connection) GetSmokeCOAlarm(deviceID string) (SmokeCOAlarm, error) {
 
 // GetSmokeCOAlarmLocale returns the locale of the specified smoke/co alarm
 func (n *Connection) GetSmokeCOAlarmLocale(deviceID string) (string, error) {
-	return n.getValue("smoke_co_alarms", deviceID, "locale")
+	var val string
+	err := n.getValue("smoke_co_alarms", deviceID, "locale", &val)
+
+	return val, err
 }
 
 // GetSmokeCOAlarmSoftwareVersion returns the software version of the specified smoke/co alarm
 func (n *Connection) GetSmokeCOAlarmSoftwareVersion(deviceID string) (string, error) {
-	return n.getValue("smoke_co_alarms", deviceID, "software_version")
+	var val string
+	err := n.getValue("smoke_co_alarms", deviceID, "software_version", &val)
+
+	return val, err
 }
 
 // GetSmokeCOAlarmName returns the name of the specified smoke/co alarm
 func (n *Connection) GetSmokeCOAlarmName(deviceID string) (string, error) {
-	return n.getValue("smoke_co_alarms", deviceID, "name")
+	var val string
+	err := n.getValue("smoke_co_alarms", deviceID, "name", &val)
+
+	return val, err
 }
 
 // GetSmokeCOAlarmLastConnection gets the last connection time of the specified smoke/co alarm
-func (n *Connection) GetSmokeCOAlarmLastConnection(deviceID string) (string, error) {
-	return n.getValue("smoke_co_alarms", deviceID, "last_connection")
+func (n *Connection) GetSmokeCOAlarmLastConnection(deviceID string) (time.Time, error) {
+	var val timestamp
+	err := n.getValue("smoke_co_alarms", deviceID, "last_connection", &val)
+
+	return val.Time, err
 }
 
 // IsSmokeCOAlarmOnline returns true if the specified smoke/co alarm is online, false if it isn't
-func (n *Connection) IsSmokeCOAlarmOnline(deviceID string) (string, error) {
-	return n.getValue("smoke_co_alarms", deviceID, "is_online")
+func (n *Connection) IsSmokeCOAlarmOnline(deviceID string) (bool, error) {
+	var val bool
+	err := n.getValue("smoke_co_alarms", deviceID, "is_online", &val)
+
+	return val, err
 }
 
 // GetSmokeCOAlarmBatteryHealth gets the battery health of the specified smoke/co alarm
-func (n *Connection) GetSmokeCOAlarmBatteryHealth(deviceID string) (string, error) {
-	return n.getValue("smoke_co_alarms", deviceID, "battery_health")
+func (n *Connection) GetSmokeCOAlarmBatteryHealth(deviceID string) (BatteryHealth, error) {
+	var val BatteryHealth
+	err := n.getValue("smoke_co_alarms", deviceID, "battery_health", &val)
+
+	return val, err
 }
 
 // GetCOAlarmState gets the Carbon Monoxide (CO) alarm status
-func (n *Connection) GetCOAlarmState(deviceID string) (string, error) {
-	return n.getValue("smoke_co_alarms", deviceID, "co_alarm_state")
+func (n *Connection) GetCOAlarmState(deviceID string) (AlarmState, error) {
+	var val AlarmState
+	err := n.getValue("smoke_co_alarms", deviceID, "co_alarm_state", &val)
+
+	return val, err
 }
 
 // GetSmokeAlarmState gets the Smoke alarm status
-func (n *Connection) GetSmokeAlarmState(deviceID string) (string, error) {
-	return n.getValue("smoke_co_alarms", deviceID, "smoke_alarm_state")
+func (n *Connection) GetSmokeAlarmState(deviceID string) (AlarmState, error) {
+	var val AlarmState
+	err := n.getValue("smoke_co_alarms", deviceID, "smoke_alarm_state", &val)
+
+	return val, err
 }
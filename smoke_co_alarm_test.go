@@ -1,6 +1,9 @@
 package nest
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestGetSmokeCOAlarms(t *testing.T) {
 	t.Run("One Smoke/CO Alarm found", func(t *testing.T) {
@@ -310,9 +313,9 @@ func TestGetSmokeCOAlarmLastConnection(t *testing.T) {
 		}
 
 		{
-			expected := "2019-01-02T14:27:53.729Z"
-			if lastConn != expected {
-				t.Fatalf("Expected Last Connection to equal %s, got %s", expected, lastConn)
+			expected := time.Date(2019, 1, 2, 14, 27, 53, 729000000, time.UTC)
+			if !lastConn.Equal(expected) {
+				t.Fatalf("Expected Last Connection to equal %v, got %v", expected, lastConn)
 			}
 		}
 	})
@@ -363,9 +366,9 @@ func TestIsSmokeCOAlarmOnline(t *testing.T) {
 		}
 
 		{
-			expected := "true"
+			expected := true
 			if isOnline != expected {
-				t.Fatalf("Expected Is Online to equal %s, got %s", expected, isOnline)
+				t.Fatalf("Expected Is Online to equal %t, got %t", expected, isOnline)
 			}
 		}
 	})
@@ -416,7 +419,7 @@ func TestGetSmokeCOAlarmBatteryHealth(t *testing.T) {
 		}
 
 		{
-			expected := "ok"
+			expected := BatteryHealthOK
 			if batteryHealth != expected {
 				t.Fatalf("Expected Battery Health to equal %s, got %s", expected, batteryHealth)
 			}
@@ -469,7 +472,7 @@ func TestGetCOAlarmState(t *testing.T) {
 		}
 
 		{
-			expected := "ok"
+			expected := AlarmStateOK
 			if alarmState != expected {
 				t.Fatalf("Expected CO Alarm State to equal %s, got %s", expected, alarmState)
 			}
@@ -522,7 +525,7 @@ func TestGetSmokeAlarmState(t *testing.T) {
 		}
 
 		{
-			expected := "ok"
+			expected := AlarmStateOK
 			if alarmState != expected {
 				t.Fatalf("Expected Smoke Alarm State to equal %s, got %s", expected, alarmState)
 			}
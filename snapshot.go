@@ -0,0 +1,139 @@
+package nest
+
+import "encoding/json"
+
+// Metadata contains info about the access token and the product using it
+type Metadata struct {
+	AccessToken   string `json:"access_token"`
+	ClientVersion int    `json:"client_version"`
+	UserID        string `json:"user_id"`
+}
+
+// Snapshot contains all the data available to the access token at one point in time,
+// with each device and structure keyed by its id
+type Snapshot struct {
+	Thermostats   map[string]Thermostat
+	SmokeCOAlarms map[string]SmokeCOAlarm
+	Cameras       map[string]Camera
+	Structures    map[string]Structure
+	Metadata      Metadata
+}
+
+// snapshotData is the layout of the top of the data model
+type snapshotData struct {
+	Devices struct {
+		Thermostats   map[string]Thermostat   `json:"thermostats"`
+		SmokeCOAlarms map[string]SmokeCOAlarm `json:"smoke_co_alarms"`
+		Cameras       map[string]Camera       `json:"cameras"`
+	} `json:"devices"`
+	Structures map[string]Structure `json:"structures"`
+	Metadata   Metadata             `json:"metadata"`
+}
+
+// UnmarshalJSON decodes the top of the data model into a snapshot
+func (s *Snapshot) UnmarshalJSON(data []byte) error {
+	d := snapshotData{}
+
+	err := json.Unmarshal(data, &d)
+	if err != nil {
+		return err
+	}
+
+	*s = Snapshot{
+		Thermostats:   d.Devices.Thermostats,
+		SmokeCOAlarms: d.Devices.SmokeCOAlarms,
+		Cameras:       d.Devices.Cameras,
+		Structures:    d.Structures,
+		Metadata:      d.Metadata,
+	}
+
+	// Use empty maps rather than nil ones when there's nothing of a type
+	if s.Thermostats == nil {
+		s.Thermostats = make(map[string]Thermostat)
+	}
+	if s.SmokeCOAlarms == nil {
+		s.SmokeCOAlarms = make(map[string]SmokeCOAlarm)
+	}
+	if s.Cameras == nil {
+		s.Cameras = make(map[string]Camera)
+	}
+	if s.Structures == nil {
+		s.Structures = make(map[string]Structure)
+	}
+
+	return nil
+}
+
+// MarshalJSON encodes the snapshot in the layout of the top of the data model
+func (s Snapshot) MarshalJSON() ([]byte, error) {
+	d := snapshotData{
+		Structures: s.Structures,
+		Metadata:   s.Metadata,
+	}
+	d.Devices.Thermostats = s.Thermostats
+	d.Devices.SmokeCOAlarms = s.SmokeCOAlarms
+	d.Devices.Cameras = s.Cameras
+
+	return json.Marshal(d)
+}
+
+// GetAll returns all devices, structures and metadata in a single request
+func (n *Connection) GetAll() (Snapshot, error) {
+	data, err := n.execute(n.rootURL(), "GET", nil)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot := Snapshot{}
+
+	// Nothing found
+	if len(data) == 0 {
+		data = []byte("{}")
+	}
+
+	err = json.Unmarshal(data, &snapshot)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	return snapshot, nil
+}
+
+// StructureThermostats returns the thermostats in the specified structure
+func (s Snapshot) StructureThermostats(structureID string) []Thermostat {
+	thermostats := []Thermostat{}
+
+	for _, id := range s.Structures[structureID].Thermostats {
+		if thermostat, ok := s.Thermostats[id]; ok {
+			thermostats = append(thermostats, thermostat)
+		}
+	}
+
+	return thermostats
+}
+
+// StructureSmokeCOAlarms returns the smoke/co alarms in the specified structure
+func (s Snapshot) StructureSmokeCOAlarms(structureID string) []SmokeCOAlarm {
+	smokeCOAlarms := []SmokeCOAlarm{}
+
+	for _, id := range s.Structures[structureID].SmokeCOAlarms {
+		if smokeCOAlarm, ok := s.SmokeCOAlarms[id]; ok {
+			smokeCOAlarms = append(smokeCOAlarms, smokeCOAlarm)
+		}
+	}
+
+	return smokeCOAlarms
+}
+
+// StructureCameras returns the cameras in the specified structure
+func (s Snapshot) StructureCameras(structureID string) []Camera {
+	cameras := []Camera{}
+
+	for _, id := range s.Structures[structureID].Cameras {
+		if camera, ok := s.Cameras[id]; ok {
+			cameras = append(cameras, camera)
+		}
+	}
+
+	return cameras
+}
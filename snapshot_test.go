@@ -0,0 +1,143 @@
+package nest
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGetAll(t *testing.T) {
+	t.Run("Data found", func(t *testing.T) {
+		n, server := createTestConnection(1)
+		defer server.Close()
+
+		snapshot, err := n.GetAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "test thermostat"
+			if snapshot.Thermostats["abc"].Name != expected {
+				t.Fatalf("Expected thermostat Name to equal %s, got %s", expected, snapshot.Thermostats["abc"].Name)
+			}
+		}
+
+		{
+			expected := "test smoke alarm"
+			if snapshot.SmokeCOAlarms["abc"].Name != expected {
+				t.Fatalf("Expected smoke/co alarm Name to equal %s, got %s", expected, snapshot.SmokeCOAlarms["abc"].Name)
+			}
+		}
+
+		{
+			expected := "test camera"
+			if snapshot.Cameras["abc"].Name != expected {
+				t.Fatalf("Expected camera Name to equal %s, got %s", expected, snapshot.Cameras["abc"].Name)
+			}
+		}
+
+		{
+			expected := "test structure"
+			if snapshot.Structures["abc"].Name != expected {
+				t.Fatalf("Expected structure Name to equal %s, got %s", expected, snapshot.Structures["abc"].Name)
+			}
+		}
+
+		{
+			expected := 1
+			if snapshot.Metadata.ClientVersion != expected {
+				t.Fatalf("Expected ClientVersion to equal %d, got %d", expected, snapshot.Metadata.ClientVersion)
+			}
+		}
+	})
+
+	t.Run("No data found", func(t *testing.T) {
+		n, server := createTestConnection(2)
+		defer server.Close()
+
+		snapshot, err := n.GetAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := 0
+			if len(snapshot.Thermostats) != expected || len(snapshot.Structures) != expected {
+				t.Fatalf("Expected %d thermostat(s) and structure(s), got %d and %d", expected, len(snapshot.Thermostats), len(snapshot.Structures))
+			}
+		}
+	})
+}
+
+func TestSnapshotStructureDevices(t *testing.T) {
+	snapshot := Snapshot{
+		Thermostats: map[string]Thermostat{
+			"t1": {DeviceID: "t1"},
+			"t2": {DeviceID: "t2"},
+		},
+		SmokeCOAlarms: map[string]SmokeCOAlarm{
+			"s1": {DeviceID: "s1"},
+		},
+		Cameras: map[string]Camera{
+			"c1": {DeviceID: "c1"},
+		},
+		Structures: map[string]Structure{
+			"home":  {StructureID: "home", Thermostats: []string{"t2", "t1"}, SmokeCOAlarms: []string{"s1"}},
+			"cabin": {StructureID: "cabin", Cameras: []string{"c1", "missing"}},
+		},
+	}
+
+	{
+		thermostats := snapshot.StructureThermostats("home")
+
+		expected := 2
+		if len(thermostats) != expected {
+			t.Fatalf("Expected %d thermostat(s), got %d", expected, len(thermostats))
+		}
+
+		if thermostats[0].DeviceID != "t2" {
+			t.Fatalf("Expected DeviceID to equal t2, got %s", thermostats[0].DeviceID)
+		}
+	}
+
+	{
+		expected := 1
+		if smokeCOAlarms := snapshot.StructureSmokeCOAlarms("home"); len(smokeCOAlarms) != expected {
+			t.Fatalf("Expected %d smoke/co alarm(s), got %d", expected, len(smokeCOAlarms))
+		}
+	}
+
+	{
+		expected := 1
+		if cameras := snapshot.StructureCameras("cabin"); len(cameras) != expected {
+			t.Fatalf("Expected %d camera(s), got %d", expected, len(cameras))
+		}
+	}
+
+	{
+		expected := 0
+		if thermostats := snapshot.StructureThermostats("unknown"); len(thermostats) != expected {
+			t.Fatalf("Expected %d thermostat(s), got %d", expected, len(thermostats))
+		}
+	}
+
+	// Snapshots round trip through the layout of the data model
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := Snapshot{}
+
+	err = json.Unmarshal(data, &decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	{
+		expected := 2
+		if len(decoded.Thermostats) != expected {
+			t.Fatalf("Expected %d thermostat(s), got %d", expected, len(decoded.Thermostats))
+		}
+	}
+}
@@ -0,0 +1,409 @@
+package nest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// Backoff used between reconnect attempts when the stream drops
+var (
+	streamMinBackoff = time.Second
+	streamMaxBackoff = time.Minute
+)
+
+// ErrAuthRevoked is sent on the stream's Errors channel when Nest revokes the access token
+var ErrAuthRevoked = errors.New("Access token has been revoked")
+
+// Stream contains the channels that real-time updates are delivered on. Every
+// channel must be drained by the caller, a channel that isn't read from will
+// block delivery on all the others. Use a StreamFanout to read only some of them
+// or to share them between consumers. All channels are closed once the stream ends.
+type Stream struct {
+	Thermostats   <-chan Thermostat
+	SmokeCOAlarms <-chan SmokeCOAlarm
+	Cameras       <-chan Camera
+	Structures    <-chan Structure
+	Errors        <-chan error
+}
+
+type streamEvent struct {
+	Path string      `json:"path"`
+	Data interface{} `json:"data"`
+}
+
+type streamSender struct {
+	ctx           context.Context
+	thermostats   chan Thermostat
+	smokeCOAlarms chan SmokeCOAlarm
+	cameras       chan Camera
+	structures    chan Structure
+	errors        chan error
+
+	// Cache kept up to date with the stream, if the connection has one
+	cache *Cache
+
+	// Full data tree built up from the put events received so far
+	state map[string]interface{}
+}
+
+// Stream opens a REST streaming connection and delivers a snapshot of every device and
+// structure whenever it changes. The stream reconnects with backoff when the connection
+// drops, and ends when ctx is cancelled or Nest sends an auth_revoked event. A failed
+// connection, such as one refused with an *APIError for an expired token, is sent on the
+// Errors channel and retried.
+func (n *Connection) Stream(ctx context.Context) *Stream {
+	s := &streamSender{
+		ctx:           ctx,
+		thermostats:   make(chan Thermostat),
+		smokeCOAlarms: make(chan SmokeCOAlarm),
+		cameras:       make(chan Camera),
+		structures:    make(chan Structure),
+		errors:        make(chan error),
+		cache:         n.Cache,
+		state:         make(map[string]interface{}),
+	}
+
+	go n.runStream(s)
+
+	return &Stream{
+		Thermostats:   s.thermostats,
+		SmokeCOAlarms: s.smokeCOAlarms,
+		Cameras:       s.cameras,
+		Structures:    s.structures,
+		Errors:        s.errors,
+	}
+}
+
+func (n *Connection) runStream(s *streamSender) {
+	defer s.close()
+
+	backoff := streamMinBackoff
+
+	for {
+		connected, err := n.readStream(s)
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			s.sendError(err)
+
+			if err == ErrAuthRevoked {
+				return
+			}
+		}
+
+		// Start over with the minimum backoff after a successful connection
+		if connected {
+			backoff = streamMinBackoff
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > streamMaxBackoff {
+			backoff = streamMaxBackoff
+		}
+	}
+}
+
+// readStream connects and processes events until the connection drops. It reports
+// whether the connection was established.
+func (n *Connection) readStream(s *streamSender) (bool, error) {
+	req, err := n.newRequest(s.ctx, "GET", n.rootURL(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Add("Accept", "text/event-stream")
+
+	// The stream stays open indefinitely, so it can't have a timeout
+	client := n.newClient()
+	client.Timeout = 0
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		data, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return false, err
+		}
+
+		return false, newAPIError(resp, data, req.URL.String())
+	}
+
+	// Reads are served from the cache without expiring while the stream is connected
+	if s.cache != nil {
+		s.cache.setLive(true)
+		defer s.cache.setLive(false)
+	}
+
+	reader := bufio.NewReader(resp.Body)
+	event, data := "", ""
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF {
+				return true, nil
+			}
+			return true, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			// A blank line dispatches the event
+			err = s.handleEvent(event, data)
+			if err != nil {
+				return true, err
+			}
+			event, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+}
+
+func (s *streamSender) handleEvent(event, data string) error {
+	switch event {
+	case "put":
+		put := streamEvent{}
+
+		err := json.Unmarshal([]byte(data), &put)
+		if err != nil {
+			return err
+		}
+
+		return s.applyPut(put)
+	case "auth_revoked":
+		return ErrAuthRevoked
+	case "error":
+		s.sendError(fmt.Errorf("Error: %s", strings.Trim(data, "\"")))
+	}
+
+	// keep-alive events and unknown events require no action
+	return nil
+}
+
+// applyPut stores the put data at its path and sends snapshots of everything it touched
+func (s *streamSender) applyPut(put streamEvent) error {
+	path := []string{}
+	for _, p := range strings.Split(put.Path, "/") {
+		if p != "" {
+			path = append(path, p)
+		}
+	}
+
+	if len(path) == 0 {
+		state, ok := put.Data.(map[string]interface{})
+		if !ok {
+			state = make(map[string]interface{})
+		}
+		s.state = state
+	} else {
+		node := s.state
+		for _, p := range path[:len(path)-1] {
+			child, ok := node[p].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				node[p] = child
+			}
+			node = child
+		}
+
+		last := path[len(path)-1]
+		if put.Data == nil {
+			delete(node, last)
+		} else {
+			node[last] = put.Data
+		}
+	}
+
+	if s.cache != nil {
+		// The cache gets its own copy, the state is changed in place by later events
+		data, err := json.Marshal(put.Data)
+		if err != nil {
+			return err
+		}
+
+		s.cache.set(strings.Join(path, "/"), data, time.Now())
+	}
+
+	switch {
+	case len(path) == 0:
+		return s.sendAll()
+	case path[0] == "devices" && len(path) == 1:
+		return s.sendDevices("")
+	case path[0] == "devices" && len(path) == 2:
+		return s.sendDevices(path[1])
+	case path[0] == "devices":
+		return s.sendDevice(path[1], path[2])
+	case path[0] == "structures" && len(path) == 1:
+		return s.sendStructures()
+	case path[0] == "structures":
+		return s.sendStructure(path[1])
+	}
+
+	return nil
+}
+
+func (s *streamSender) sendAll() error {
+	err := s.sendDevices("")
+	if err != nil {
+		return err
+	}
+
+	return s.sendStructures()
+}
+
+// sendDevices sends every device of deviceType, or of all types if deviceType is empty
+func (s *streamSender) sendDevices(deviceType string) error {
+	devices, _ := s.state["devices"].(map[string]interface{})
+
+	for _, t := range []string{"thermostats", "smoke_co_alarms", "cameras"} {
+		if deviceType != "" && deviceType != t {
+			continue
+		}
+
+		all, _ := devices[t].(map[string]interface{})
+		for id := range all {
+			err := s.sendDevice(t, id)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *streamSender) sendDevice(deviceType, deviceID string) error {
+	devices, _ := s.state["devices"].(map[string]interface{})
+	all, _ := devices[deviceType].(map[string]interface{})
+
+	val, ok := all[deviceID]
+	if !ok {
+		return nil
+	}
+
+	d, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	switch deviceType {
+	case "thermostats":
+		thermostat := Thermostat{}
+
+		err = json.Unmarshal(d, &thermostat)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case s.thermostats <- thermostat:
+		case <-s.ctx.Done():
+		}
+	case "smoke_co_alarms":
+		smokeCOAlarm := SmokeCOAlarm{}
+
+		err = json.Unmarshal(d, &smokeCOAlarm)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case s.smokeCOAlarms <- smokeCOAlarm:
+		case <-s.ctx.Done():
+		}
+	case "cameras":
+		camera := Camera{}
+
+		err = json.Unmarshal(d, &camera)
+		if err != nil {
+			return err
+		}
+
+		select {
+		case s.cameras <- camera:
+		case <-s.ctx.Done():
+		}
+	}
+
+	return nil
+}
+
+func (s *streamSender) sendStructures() error {
+	all, _ := s.state["structures"].(map[string]interface{})
+
+	for id := range all {
+		err := s.sendStructure(id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *streamSender) sendStructure(structureID string) error {
+	all, _ := s.state["structures"].(map[string]interface{})
+
+	val, ok := all[structureID]
+	if !ok {
+		return nil
+	}
+
+	d, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	structure := Structure{}
+
+	err = json.Unmarshal(d, &structure)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case s.structures <- structure:
+	case <-s.ctx.Done():
+	}
+
+	return nil
+}
+
+func (s *streamSender) sendError(err error) {
+	select {
+	case s.errors <- err:
+	case <-s.ctx.Done():
+	}
+}
+
+func (s *streamSender) close() {
+	close(s.thermostats)
+	close(s.smokeCOAlarms)
+	close(s.cameras)
+	close(s.structures)
+	close(s.errors)
+}
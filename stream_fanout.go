@@ -0,0 +1,214 @@
+package nest
+
+import (
+	"context"
+	"sync"
+)
+
+// StreamTypes selects the updates a StreamFanout subscription receives
+type StreamTypes struct {
+	Thermostats   bool
+	SmokeCOAlarms bool
+	Cameras       bool
+	Structures    bool
+	Errors        bool
+}
+
+// StreamFanout reads every channel of a Stream and delivers its updates to any number of
+// subscribers, so a stream can be shared and a consumer only has to read the updates it
+// needs. Each subscriber must drain the channels it subscribed to, a subscriber that
+// stops reading blocks delivery to all the others. The zero value is ready to use.
+type StreamFanout struct {
+	mu          sync.Mutex
+	subscribers []*streamSubscriber
+	closed      bool
+}
+
+// streamSubscriber holds the channels of a subscription, nil for the types it didn't
+// subscribe to
+type streamSubscriber struct {
+	thermostats   chan Thermostat
+	smokeCOAlarms chan SmokeCOAlarm
+	cameras       chan Camera
+	structures    chan Structure
+	errors        chan error
+}
+
+// Subscribe returns a Stream that receives the types of update selected by types. Its
+// channels for the other types are nil. Subscribe before calling Run so that nothing sent
+// at the start of the stream is missed, a subscription made later only receives the
+// updates from then on.
+func (f *StreamFanout) Subscribe(types StreamTypes) *Stream {
+	sub := &streamSubscriber{}
+	if types.Thermostats {
+		sub.thermostats = make(chan Thermostat)
+	}
+	if types.SmokeCOAlarms {
+		sub.smokeCOAlarms = make(chan SmokeCOAlarm)
+	}
+	if types.Cameras {
+		sub.cameras = make(chan Camera)
+	}
+	if types.Structures {
+		sub.structures = make(chan Structure)
+	}
+	if types.Errors {
+		sub.errors = make(chan error)
+	}
+
+	f.mu.Lock()
+	if f.closed {
+		sub.close()
+	} else {
+		f.subscribers = append(f.subscribers, sub)
+	}
+	f.mu.Unlock()
+
+	return &Stream{
+		Thermostats:   sub.thermostats,
+		SmokeCOAlarms: sub.smokeCOAlarms,
+		Cameras:       sub.cameras,
+		Structures:    sub.structures,
+		Errors:        sub.errors,
+	}
+}
+
+// Run delivers the updates from s to the subscribers until every channel of s is closed
+// or ctx is cancelled, then closes the subscriptions. ctx is normally the one the stream
+// was opened with, as s is no longer read from once Run returns.
+func (f *StreamFanout) Run(ctx context.Context, s *Stream) {
+	defer f.close()
+
+	thermostats, alarms, cameras, structures, errs := s.Thermostats, s.SmokeCOAlarms, s.Cameras, s.Structures, s.Errors
+
+	for thermostats != nil || alarms != nil || cameras != nil || structures != nil || errs != nil {
+		select {
+		case <-ctx.Done():
+			return
+		case thermostat, ok := <-thermostats:
+			if !ok {
+				thermostats = nil
+				continue
+			}
+
+			for _, sub := range f.subscribed() {
+				if sub.thermostats == nil {
+					continue
+				}
+
+				select {
+				case sub.thermostats <- thermostat:
+				case <-ctx.Done():
+					return
+				}
+			}
+		case alarm, ok := <-alarms:
+			if !ok {
+				alarms = nil
+				continue
+			}
+
+			for _, sub := range f.subscribed() {
+				if sub.smokeCOAlarms == nil {
+					continue
+				}
+
+				select {
+				case sub.smokeCOAlarms <- alarm:
+				case <-ctx.Done():
+					return
+				}
+			}
+		case camera, ok := <-cameras:
+			if !ok {
+				cameras = nil
+				continue
+			}
+
+			for _, sub := range f.subscribed() {
+				if sub.cameras == nil {
+					continue
+				}
+
+				select {
+				case sub.cameras <- camera:
+				case <-ctx.Done():
+					return
+				}
+			}
+		case structure, ok := <-structures:
+			if !ok {
+				structures = nil
+				continue
+			}
+
+			for _, sub := range f.subscribed() {
+				if sub.structures == nil {
+					continue
+				}
+
+				select {
+				case sub.structures <- structure:
+				case <-ctx.Done():
+					return
+				}
+			}
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+
+			for _, sub := range f.subscribed() {
+				if sub.errors == nil {
+					continue
+				}
+
+				select {
+				case sub.errors <- err:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}
+}
+
+// subscribed returns the current subscribers
+func (f *StreamFanout) subscribed() []*streamSubscriber {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]*streamSubscriber{}, f.subscribers...)
+}
+
+// close ends every subscription, and any made after it
+func (f *StreamFanout) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, sub := range f.subscribers {
+		sub.close()
+	}
+
+	f.subscribers = nil
+	f.closed = true
+}
+
+func (sub *streamSubscriber) close() {
+	if sub.thermostats != nil {
+		close(sub.thermostats)
+	}
+	if sub.smokeCOAlarms != nil {
+		close(sub.smokeCOAlarms)
+	}
+	if sub.cameras != nil {
+		close(sub.cameras)
+	}
+	if sub.structures != nil {
+		close(sub.structures)
+	}
+	if sub.errors != nil {
+		close(sub.errors)
+	}
+}
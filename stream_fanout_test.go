@@ -0,0 +1,83 @@
+package nest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStreamFanout(t *testing.T) {
+	streamMinBackoff = time.Millisecond
+	defer func() { streamMinBackoff = time.Second }()
+
+	t.Run("Subscribers", func(t *testing.T) {
+		n, server := createTestStreamConnection(t,
+			"event: put\ndata: {\"path\":\"/\",\"data\":{\"devices\":{\"thermostats\":{\"abc\":{\"device_id\":\"abc\",\"is_online\":true}},"+
+				"\"smoke_co_alarms\":{\"def\":{\"device_id\":\"def\",\"is_online\":true}},\"cameras\":{\"ghi\":{\"device_id\":\"ghi\",\"is_online\":true}}},"+
+				"\"structures\":{\"abc123\":{\"structure_id\":\"abc123\",\"away\":\"home\"}}}}\n\n",
+			"event: put\ndata: {\"path\":\"/devices/thermostats/abc/is_online\",\"data\":false}\n\n",
+			"event: auth_revoked\ndata: \"TEST\"\n\n",
+		)
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		f := &StreamFanout{}
+		safety := f.Subscribe(StreamTypes{SmokeCOAlarms: true, Structures: true})
+		connectivity := f.Subscribe(StreamTypes{Thermostats: true, SmokeCOAlarms: true, Cameras: true})
+
+		go f.Run(ctx, n.Stream(ctx))
+
+		m := &SafetyMonitor{}
+		c := &ConnectivityTracker{}
+		errs := make([]error, 2)
+
+		wg := sync.WaitGroup{}
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			errs[0] = m.Watch(ctx, safety.SmokeCOAlarms, safety.Structures)
+		}()
+		go func() {
+			defer wg.Done()
+			errs[1] = c.Watch(ctx, connectivity.Thermostats, connectivity.SmokeCOAlarms, connectivity.Cameras)
+		}()
+		wg.Wait()
+
+		for _, err := range errs {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, deviceID := range []string{"abc", "def", "ghi"} {
+			if _, seen := c.IsOnline(deviceID); !seen {
+				t.Fatalf("Expected device %s to have been observed", deviceID)
+			}
+		}
+
+		{
+			expected := 1
+			if len(c.Transitions("abc")) != expected {
+				t.Fatalf("Expected %d recorded transition(s), got %d", expected, len(c.Transitions("abc")))
+			}
+		}
+
+		if safety.Thermostats != nil || safety.Cameras != nil || safety.Errors != nil {
+			t.Fatal("Expected channels that weren't subscribed to to be nil")
+		}
+	})
+
+	t.Run("Subscribed after close", func(t *testing.T) {
+		f := &StreamFanout{}
+		f.Run(context.Background(), &Stream{})
+
+		s := f.Subscribe(StreamTypes{Errors: true})
+
+		if _, ok := <-s.Errors; ok {
+			t.Fatal("Expected the subscription to be closed")
+		}
+	})
+}
@@ -0,0 +1,183 @@
+package nest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func createTestStreamConnection(t *testing.T, events ...string) (Connection, *httptest.Server) {
+	connections := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Nest redirects streaming requests to a Firebase host
+		if r.URL.Path != "/stream" {
+			http.Redirect(w, r, "/stream", http.StatusTemporaryRedirect)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer TEST" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("Expected Accept header to equal text/event-stream, got %s", r.Header.Get("Accept"))
+		}
+
+		// Each connection sends the next event, then drops
+		if connections < len(events) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, events[connections])
+		}
+		connections++
+	}))
+
+	return Connection{
+		AccessToken: "TEST",
+		testURL:     fmt.Sprintf("%s/devices", server.URL),
+	}, server
+}
+
+func TestStream(t *testing.T) {
+	streamMinBackoff = time.Millisecond
+	defer func() { streamMinBackoff = time.Second }()
+
+	t.Run("Updates received", func(t *testing.T) {
+		n, server := createTestStreamConnection(t,
+			"event: keep-alive\ndata: null\n\n"+
+				"event: put\ndata: {\"path\":\"/\",\"data\":{\"devices\":{\"thermostats\":{\"abc\":{\"device_id\":\"abc\",\"target_temperature_f\":68}},"+
+				"\"smoke_co_alarms\":{\"def\":{\"device_id\":\"def\"}},\"cameras\":{\"ghi\":{\"device_id\":\"ghi\"}}},"+
+				"\"structures\":{\"abc123\":{\"structure_id\":\"abc123\",\"away\":\"home\"}}}}\n\n",
+			"event: put\ndata: {\"path\":\"/devices/thermostats/abc/target_temperature_f\",\"data\":70}\n\n",
+			"event: auth_revoked\ndata: \"TEST\"\n\n",
+		)
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		s := n.Stream(ctx)
+
+		thermostats := []Thermostat{}
+		smokeCOAlarms, cameras, structures := 0, 0, 0
+		var streamErr error
+
+		for s.Thermostats != nil || s.SmokeCOAlarms != nil || s.Cameras != nil || s.Structures != nil || s.Errors != nil {
+			select {
+			case thermostat, ok := <-s.Thermostats:
+				if !ok {
+					s.Thermostats = nil
+					continue
+				}
+				thermostats = append(thermostats, thermostat)
+			case _, ok := <-s.SmokeCOAlarms:
+				if !ok {
+					s.SmokeCOAlarms = nil
+					continue
+				}
+				smokeCOAlarms++
+			case _, ok := <-s.Cameras:
+				if !ok {
+					s.Cameras = nil
+					continue
+				}
+				cameras++
+			case _, ok := <-s.Structures:
+				if !ok {
+					s.Structures = nil
+					continue
+				}
+				structures++
+			case err, ok := <-s.Errors:
+				if !ok {
+					s.Errors = nil
+					continue
+				}
+				streamErr = err
+			}
+		}
+
+		{
+			expected := 2
+			if len(thermostats) != expected {
+				t.Fatalf("Expected %d thermostat update(s), got %d", expected, len(thermostats))
+			}
+		}
+
+		{
+			expected := 70
+			if thermostats[1].TargetTemperatureF != expected {
+				t.Fatalf("Expected TargetTemperatureF to equal %d, got %d", expected, thermostats[1].TargetTemperatureF)
+			}
+		}
+
+		{
+			expected := 1
+			if smokeCOAlarms != expected || cameras != expected || structures != expected {
+				t.Fatalf("Expected %d update(s) of each, got %d smoke/co alarm(s), %d camera(s), %d structure(s)", expected, smokeCOAlarms, cameras, structures)
+			}
+		}
+
+		{
+			expected := ErrAuthRevoked
+			if streamErr != expected {
+				t.Fatalf("Expected error to equal %v, got %v", expected, streamErr)
+			}
+		}
+	})
+
+	t.Run("Invalid access token", func(t *testing.T) {
+		n, server := createTestStreamConnection(t)
+		defer server.Close()
+
+		n.AccessToken = "INVALID"
+
+		ctx, cancel := context.WithCancel(context.Background())
+		s := n.Stream(ctx)
+
+		err := <-s.Errors
+		{
+			expected := ErrUnauthorized
+			if !errors.Is(err, expected) {
+				t.Fatalf("Expected error to equal %v, got %v", expected, err)
+			}
+		}
+
+		// Only the auth_revoked event means the token has been revoked
+		if errors.Is(err, ErrAuthRevoked) {
+			t.Fatalf("Expected error not to equal %v", ErrAuthRevoked)
+		}
+
+		apiErr := &APIError{}
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Expected an *APIError, got %T", err)
+		}
+
+		cancel()
+
+		for range s.Errors {
+		}
+
+		if _, ok := <-s.Thermostats; ok {
+			t.Fatal("Expected stream to be closed")
+		}
+	})
+
+	t.Run("Context cancelled", func(t *testing.T) {
+		n, server := createTestStreamConnection(t)
+		defer server.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		s := n.Stream(ctx)
+		cancel()
+
+		if _, ok := <-s.Errors; ok {
+			t.Fatal("Expected stream to be closed")
+		}
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 type structureWheres struct {
@@ -18,20 +19,45 @@ type Structure struct {
 	Thermostats         []string        `json:"thermostats"`
 	SmokeCOAlarms       []string        `json:"smoke_co_alarms"`
 	Cameras             []string        `json:"cameras"`
-	Away                string          `json:"away"`
+	Away                AwayState       `json:"away"`
 	Name                string          `json:"name"`
 	CountryCode         string          `json:"country_code"`
 	PostalCode          string          `json:"postal_code"`
-	PeakPeriodStartTime string          `json:"peak_period_start_time"`
-	PeakPeriodEndTime   string          `json:"peak_period_end_time"`
+	PeakPeriodStartTime time.Time       `json:"peak_period_start_time"`
+	PeakPeriodEndTime   time.Time       `json:"peak_period_end_time"`
 	TimeZone            string          `json:"time_zone"`
 	ETA                 []string        `json:"eta"`
-	ETABegin            string          `json:"eta_begin"`
+	ETABegin            time.Time       `json:"eta_begin"`
 	RHREnrollment       bool            `json:"rhr_enrollment"`
-	WWNSecurityState    string          `json:"wwn_security_state"`
+	WWNSecurityState    SecurityState   `json:"wwn_security_state"`
 	Wheres              structureWheres `json:"wheres"`
-	COAlarmState        string          `json:"co_alarm_state"`
-	SmokeAlarmState     string          `json:"smoke_alarm_state"`
+	COAlarmState        AlarmState      `json:"co_alarm_state"`
+	SmokeAlarmState     AlarmState      `json:"smoke_alarm_state"`
+}
+
+// UnmarshalJSON decodes a structure, leaving timestamps that are empty as the zero time
+func (s *Structure) UnmarshalJSON(data []byte) error {
+	type structure Structure
+
+	aux := struct {
+		*structure
+		PeakPeriodStartTime timestamp `json:"peak_period_start_time"`
+		PeakPeriodEndTime   timestamp `json:"peak_period_end_time"`
+		ETABegin            timestamp `json:"eta_begin"`
+	}{
+		structure: (*structure)(s),
+	}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	s.PeakPeriodStartTime = aux.PeakPeriodStartTime.Time
+	s.PeakPeriodEndTime = aux.PeakPeriodEndTime.Time
+	s.ETABegin = aux.ETABegin.Time
+
+	return nil
 }
 
 // GetStructures returns all Nest structures along with all their data
@@ -107,26 +133,105 @@ func (n *Connection) GetStructure(structureID string) (Structure, error) {
 }
 
 // GetStructureThermostats returns a list of termostats in the specified structure
-func (n *Connection) GetStructureThermostats(structureID string) (string, error) {
-	return n.getValue("structures", structureID, "thermostats")
+func (n *Connection) GetStructureThermostats(structureID string) ([]string, error) {
+	var val []string
+	err := n.getValue("structures", structureID, "thermostats", &val)
+
+	return val, err
 }
 
 // GetStructureSmokeCOAlarms returns a list of smoke/co alarms in the specified structure
-func (n *Connection) GetStructureSmokeCOAlarms(structureID string) (string, error) {
-	return n.getValue("structures", structureID, "smoke_co_alarms")
+func (n *Connection) GetStructureSmokeCOAlarms(structureID string) ([]string, error) {
+	var val []string
+	err := n.getValue("structures", structureID, "smoke_co_alarms", &val)
+
+	return val, err
 }
 
 // GetStructureCameras returns a list of cameras in the specified structure
-func (n *Connection) GetStructureCameras(structureID string) (string, error) {
-	return n.getValue("structures", structureID, "cameras")
+func (n *Connection) GetStructureCameras(structureID string) ([]string, error) {
+	var val []string
+	err := n.getValue("structures", structureID, "cameras", &val)
+
+	return val, err
 }
 
 // GetStructureOccupancyState returns the occupancy state (home or away) for the specified structure
-func (n *Connection) GetStructureOccupancyState(structureID string) (string, error) {
-	return n.getValue("structures", structureID, "away")
+func (n *Connection) GetStructureOccupancyState(structureID string) (AwayState, error) {
+	var val AwayState
+	err := n.getValue("structures", structureID, "away", &val)
+
+	return val, err
 }
 
 // GetStructureName returns the name of the specified structure
 func (n *Connection) GetStructureName(structureID string) (string, error) {
-	return n.getValue("structures", structureID, "name")
+	var val string
+	err := n.getValue("structures", structureID, "name", &val)
+
+	return val, err
+}
+
+// SetStructureAway sets the occupancy state (home or away) of the specified structure and
+// returns the state Nest applied
+func (n *Connection) SetStructureAway(structureID string, away AwayState) (AwayState, error) {
+	// Error checking
+	away = AwayState(strings.Trim(away.String(), " "))
+
+	err := away.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	confirmed := structureRequest{}
+
+	err = n.setValue("structures", structureID, structureRequest{Away: away}, &confirmed)
+	if err != nil {
+		return "", err
+	}
+
+	return confirmed.Away, nil
+}
+
+// SetStructureETA tells the specified structure that someone is expected to arrive
+// between begin and end, so it can get ready for them. The same trip id should be used
+// to update the estimate for a trip. It returns the window Nest applied.
+func (n *Connection) SetStructureETA(structureID, tripID string, begin, end time.Time) (time.Time, time.Time, error) {
+	// Error checking
+	if strings.Trim(tripID, " ") == "" {
+		return time.Time{}, time.Time{}, errors.New("Trip ID must not be empty")
+	}
+
+	if begin.IsZero() || end.IsZero() {
+		return time.Time{}, time.Time{}, errors.New("Estimated arrival window must not be empty")
+	}
+
+	if end.Before(begin) {
+		return time.Time{}, time.Time{}, errors.New("Estimated arrival window must not end before it begins")
+	}
+
+	req := etaRequest{
+		TripID:                      tripID,
+		EstimatedArrivalWindowBegin: etaTime(begin),
+		EstimatedArrivalWindowEnd:   (*etaTime)(&end),
+	}
+
+	confirmed := etaResponse{}
+
+	err := n.setFieldValue("structures", structureID, "eta", req, &confirmed)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return confirmed.EstimatedArrivalWindowBegin.Time, confirmed.EstimatedArrivalWindowEnd.Time, nil
+}
+
+// CancelStructureETA cancels the ETA of the specified trip
+func (n *Connection) CancelStructureETA(structureID, tripID string) error {
+	// Error checking
+	if strings.Trim(tripID, " ") == "" {
+		return errors.New("Trip ID must not be empty")
+	}
+
+	return n.setFieldValue("structures", structureID, "eta", etaRequest{TripID: tripID}, nil)
 }
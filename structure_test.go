@@ -1,6 +1,11 @@
 package nest
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestGetStructures(t *testing.T) {
 	t.Run("One structure found", func(t *testing.T) {
@@ -27,7 +32,7 @@ func TestGetStructures(t *testing.T) {
 		}
 
 		{
-			expected := "home"
+			expected := AwayStateHome
 			if structures[0].Away != expected {
 				t.Fatalf("Expected Away to equal %s, got %s", expected, structures[0].Away)
 			}
@@ -84,7 +89,7 @@ func TestGetStructure(t *testing.T) {
 		}
 
 		{
-			expected := "home"
+			expected := AwayStateHome
 			if structure.Away != expected {
 				t.Fatalf("Expected Away to equal %s, got %s", expected, structure.Away)
 			}
@@ -151,9 +156,9 @@ func TestGetStructureThermostats(t *testing.T) {
 		}
 
 		{
-			expected := "[\"123\"]"
-			if thermostats != expected {
-				t.Fatalf("Expected Thermostats to equal %s, got %s", expected, thermostats)
+			expected := []string{"123"}
+			if !reflect.DeepEqual(thermostats, expected) {
+				t.Fatalf("Expected Thermostats to equal %v, got %v", expected, thermostats)
 			}
 		}
 	})
@@ -204,9 +209,9 @@ func TestGetStructureSmokeCOAlarms(t *testing.T) {
 		}
 
 		{
-			expected := "[\"456\"]"
-			if alarms != expected {
-				t.Fatalf("Expected Smoke/CO Alarms to equal %s, got %s", expected, alarms)
+			expected := []string{"456"}
+			if !reflect.DeepEqual(alarms, expected) {
+				t.Fatalf("Expected Smoke/CO Alarms to equal %v, got %v", expected, alarms)
 			}
 		}
 	})
@@ -257,9 +262,9 @@ func TestGetStructureCameras(t *testing.T) {
 		}
 
 		{
-			expected := "[\"789\"]"
-			if cameras != expected {
-				t.Fatalf("Expected Cameras to equal %s, got %s", expected, cameras)
+			expected := []string{"789"}
+			if !reflect.DeepEqual(cameras, expected) {
+				t.Fatalf("Expected Cameras to equal %v, got %v", expected, cameras)
 			}
 		}
 	})
@@ -310,7 +315,7 @@ func TestGetStructureOccupancyState(t *testing.T) {
 		}
 
 		{
-			expected := "home"
+			expected := AwayStateHome
 			if state != expected {
 				t.Fatalf("Expected Occupancy State to equal %s, got %s", expected, state)
 			}
@@ -404,3 +409,141 @@ func TestGetStructureName(t *testing.T) {
 		}
 	})
 }
+
+func TestSetStructureAway(t *testing.T) {
+	n, server := createTestConnection(1)
+	defer server.Close()
+
+	t.Run("Success", func(t *testing.T) {
+		_, err := n.SetStructureAway("abc", "away")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Invalid structure id", func(t *testing.T) {
+		_, err := n.SetStructureAway("", "away")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Structure ID must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Empty away", func(t *testing.T) {
+		_, err := n.SetStructureAway("abc", "")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Away must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Invalid away", func(t *testing.T) {
+		_, err := n.SetStructureAway("abc", "auto-away")
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Away must be one of the following: [home away]"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+}
+
+func TestSetStructureETA(t *testing.T) {
+	n, server, write := createTestWriteConnection(nil)
+	defer server.Close()
+
+	begin := time.Date(2019, 1, 2, 14, 0, 0, 0, time.UTC)
+	end := begin.Add(30 * time.Minute)
+
+	t.Run("Success", func(t *testing.T) {
+		_, _, err := n.SetStructureETA("abc", "trip1", begin, end)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := "/devices/structures/abc/eta"
+			if write.path != expected {
+				t.Fatalf("Expected path to equal %s, got %s", expected, write.path)
+			}
+		}
+
+		for _, expected := range []string{`"trip_id":"trip1"`, `"estimated_arrival_window_begin":"2019-01-02T14:00:00.000Z"`, `"estimated_arrival_window_end":"2019-01-02T14:30:00.000Z"`} {
+			if !strings.Contains(write.body, expected) {
+				t.Fatalf("Expected body to contain %s, got %s", expected, write.body)
+			}
+		}
+	})
+
+	t.Run("Invalid structure id", func(t *testing.T) {
+		_, _, err := n.SetStructureETA("", "trip1", begin, end)
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Structure ID must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Empty trip id", func(t *testing.T) {
+		_, _, err := n.SetStructureETA("abc", "", begin, end)
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Trip ID must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Invalid window", func(t *testing.T) {
+		_, _, err := n.SetStructureETA("abc", "trip1", end, begin)
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Estimated arrival window must not end before it begins"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		err := n.CancelStructureETA("abc", "trip1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := `"estimated_arrival_window_begin":0`
+			if !strings.Contains(write.body, expected) {
+				t.Fatalf("Expected body to contain %s, got %s", expected, write.body)
+			}
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package nest
+
+import (
+	"fmt"
+	"math"
+)
+
+// Temperature is a temperature in either scale
+type Temperature struct {
+	Value float64
+	Scale TemperatureScale
+}
+
+// Fahrenheit returns a temperature of v°F
+func Fahrenheit(v float64) Temperature {
+	return Temperature{Value: v, Scale: TemperatureScaleF}
+}
+
+// Celsius returns a temperature of v°C
+func Celsius(v float64) Temperature {
+	return Temperature{Value: v, Scale: TemperatureScaleC}
+}
+
+// InFahrenheit returns the temperature in °F, rounded to a whole degree like Nest does
+func (t Temperature) InFahrenheit() float64 {
+	v := t.Value
+	if t.Scale == TemperatureScaleC {
+		v = v*9/5 + 32
+	}
+
+	return math.Round(v)
+}
+
+// InCelsius returns the temperature in °C, rounded to the nearest half degree like Nest does
+func (t Temperature) InCelsius() float64 {
+	v := t.Value
+	if t.Scale == TemperatureScaleF {
+		v = (v - 32) * 5 / 9
+	}
+
+	return math.Round(v*2) / 2
+}
+
+// In returns the temperature converted to scale and rounded
+func (t Temperature) In(scale TemperatureScale) Temperature {
+	if scale == TemperatureScaleF {
+		return Fahrenheit(t.InFahrenheit())
+	}
+
+	return Celsius(t.InCelsius())
+}
+
+func (t Temperature) String() string {
+	return fmt.Sprintf("%g°%s", t.Value, t.Scale)
+}
+
+// validate returns an error unless the temperature is in the range a thermostat can be set to
+func (t Temperature) validate(name string) error {
+	err := t.Scale.Validate()
+	if err != nil {
+		return err
+	}
+
+	if t.Scale == TemperatureScaleF {
+		if v := t.InFahrenheit(); v < 50 || v > 90 {
+			return fmt.Errorf("%s must be in the range of 50 - 90", name)
+		}
+	} else {
+		if v := t.InCelsius(); v < 9 || v > 32 {
+			return fmt.Errorf("%s must be in the range of 9 - 32", name)
+		}
+	}
+
+	return nil
+}
+
+// TargetTemperature returns the target temperature of the thermostat in its temperature scale
+func (t Thermostat) TargetTemperature() Temperature {
+	if t.TemperatureScale == TemperatureScaleC {
+		return Celsius(float64(t.TargetTemperatureC))
+	}
+
+	return Fahrenheit(float64(t.TargetTemperatureF))
+}
+
+// AmbientTemperature returns the ambient temperature of the thermostat in its temperature scale
+func (t Thermostat) AmbientTemperature() Temperature {
+	if t.TemperatureScale == TemperatureScaleC {
+		return Celsius(float64(t.AmbientTemperatureC))
+	}
+
+	return Fahrenheit(float64(t.AmbientTemperatureF))
+}
+
+// SetTargetTemperature changes the target temperature of the specified thermostat. The
+// temperature is written in its own scale, whatever scale the thermostat displays. The
+// thermostat is fetched first and a SetpointError is returned if it wouldn't accept temp.
+// The temperature Nest applied is returned.
+func (n *Connection) SetTargetTemperature(deviceID string, temp Temperature) (Temperature, error) {
+	// Error checking
+	err := temp.validate("Target Temperature")
+	if err != nil {
+		return Temperature{}, err
+	}
+
+	thermostat, err := n.GetThermostat(deviceID)
+	if err != nil {
+		return Temperature{}, err
+	}
+
+	err = thermostat.ValidateTargetTemperature(temp)
+	if err != nil {
+		return Temperature{}, err
+	}
+
+	req := thermostatRequest{}
+	req.setTargetTemperature(temp)
+
+	confirmed := thermostatRequest{}
+
+	err = n.setValue("thermostats", deviceID, req, &confirmed)
+	if err != nil {
+		return Temperature{}, err
+	}
+
+	return temperatureFromFields(confirmed.TargetTemperatureF, confirmed.TargetTemperatureC), nil
+}
+
+// SetTargetRange changes the target low and high temperatures of the specified
+// thermostat, used in heat-cool mode. The temperatures are written in the scale of high,
+// whatever scale the thermostat displays. The thermostat is fetched first and a
+// SetpointError is returned if it wouldn't accept the range. The low and high
+// temperatures Nest applied are returned.
+func (n *Connection) SetTargetRange(deviceID string, low, high Temperature) (Temperature, Temperature, error) {
+	// Error checking
+	err := validateRange(low, high)
+	if err != nil {
+		return Temperature{}, Temperature{}, err
+	}
+
+	thermostat, err := n.GetThermostat(deviceID)
+	if err != nil {
+		return Temperature{}, Temperature{}, err
+	}
+
+	err = thermostat.ValidateTargetRange(low, high)
+	if err != nil {
+		return Temperature{}, Temperature{}, err
+	}
+
+	req := thermostatRequest{}
+	req.setTargetRange(low, high)
+
+	confirmed := thermostatRequest{}
+
+	err = n.setValue("thermostats", deviceID, req, &confirmed)
+	if err != nil {
+		return Temperature{}, Temperature{}, err
+	}
+
+	low = temperatureFromFields(confirmed.TargetTemperatureLowF, confirmed.TargetTemperatureLowC)
+	high = temperatureFromFields(confirmed.TargetTemperatureHighF, confirmed.TargetTemperatureHighC)
+
+	return low, high, nil
+}
@@ -0,0 +1,185 @@
+package nest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTemperatureConversion(t *testing.T) {
+	tests := []struct {
+		temp        Temperature
+		fahrenheit  float64
+		celsius     float64
+		description string
+	}{
+		{Fahrenheit(68), 68, 20, "68°F"},
+		{Fahrenheit(71), 71, 21.5, "71°F"},
+		{Fahrenheit(70.6), 71, 21.5, "70.6°F"},
+		{Celsius(21.5), 71, 21.5, "21.5°C"},
+		{Celsius(21.3), 70, 21.5, "21.3°C"},
+		{Celsius(21.2), 70, 21, "21.2°C"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			if test.temp.String() != test.description {
+				t.Fatalf("Expected String to equal %s, got %s", test.description, test.temp.String())
+			}
+
+			if test.temp.InFahrenheit() != test.fahrenheit {
+				t.Fatalf("Expected InFahrenheit to equal %v, got %v", test.fahrenheit, test.temp.InFahrenheit())
+			}
+
+			if test.temp.InCelsius() != test.celsius {
+				t.Fatalf("Expected InCelsius to equal %v, got %v", test.celsius, test.temp.InCelsius())
+			}
+		})
+	}
+}
+
+func TestThermostatTemperatures(t *testing.T) {
+	thermostat := Thermostat{
+		TemperatureScale:    TemperatureScaleC,
+		TargetTemperatureC:  21.5,
+		TargetTemperatureF:  71,
+		AmbientTemperatureC: 20,
+		AmbientTemperatureF: 68,
+	}
+
+	{
+		expected := Celsius(21.5)
+		if thermostat.TargetTemperature() != expected {
+			t.Fatalf("Expected TargetTemperature to equal %s, got %s", expected, thermostat.TargetTemperature())
+		}
+	}
+
+	thermostat.TemperatureScale = TemperatureScaleF
+
+	{
+		expected := Fahrenheit(68)
+		if thermostat.AmbientTemperature() != expected {
+			t.Fatalf("Expected AmbientTemperature to equal %s, got %s", expected, thermostat.AmbientTemperature())
+		}
+	}
+}
+
+func TestSetTargetTemperature(t *testing.T) {
+	n, server, write := createTestWriteConnection(nil)
+	defer server.Close()
+
+	t.Run("Fahrenheit", func(t *testing.T) {
+		_, err := n.SetTargetTemperature("def", Fahrenheit(70.4))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := `{"target_temperature_f":70}`
+			if write.body != expected {
+				t.Fatalf("Expected body to equal %s, got %s", expected, write.body)
+			}
+		}
+	})
+
+	t.Run("Celsius", func(t *testing.T) {
+		_, err := n.SetTargetTemperature("def", Celsius(21.4))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := `{"target_temperature_c":21.5}`
+			if write.body != expected {
+				t.Fatalf("Expected body to equal %s, got %s", expected, write.body)
+			}
+		}
+	})
+
+	t.Run("Invalid device id", func(t *testing.T) {
+		_, err := n.SetTargetTemperature("", Fahrenheit(70))
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Device ID must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Invalid temperature", func(t *testing.T) {
+		_, err := n.SetTargetTemperature("abc", Celsius(40))
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Target Temperature must be in the range of 9 - 32"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Invalid scale", func(t *testing.T) {
+		_, err := n.SetTargetTemperature("abc", Temperature{Value: 70})
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Temperature Scale must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+}
+
+func TestSetTargetRange(t *testing.T) {
+	n, server, write := createTestWriteConnection(nil)
+	defer server.Close()
+
+	t.Run("Mixed scales", func(t *testing.T) {
+		_, _, err := n.SetTargetRange("abc", Celsius(20), Fahrenheit(74))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, expected := range []string{`"target_temperature_low_f":68`, `"target_temperature_high_f":74`} {
+			if !strings.Contains(write.body, expected) {
+				t.Fatalf("Expected body to contain %s, got %s", expected, write.body)
+			}
+		}
+	})
+
+	t.Run("Low above high", func(t *testing.T) {
+		_, _, err := n.SetTargetRange("abc", Celsius(24), Celsius(22))
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Target Low Temperature must be lower than Target High Temperature"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Invalid high temperature", func(t *testing.T) {
+		_, _, err := n.SetTargetRange("abc", Fahrenheit(70), Fahrenheit(95))
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Target High Temperature must be in the range of 50 - 90"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+}
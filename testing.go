@@ -3,8 +3,10 @@ package nest
 import (
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"time"
 )
 
 type thermostatTestData struct {
@@ -24,31 +26,115 @@ type structureTestData struct {
 }
 
 func createTestConnection(scenario int) (Connection, *httptest.Server) {
+	if scenario == 1 {
+		n, server, _ := createTestWriteConnection(nil)
+		return n, server
+	}
+
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if scenario == 1 {
-			if r.Method == "GET" {
-				returnData := generateTestData(r.URL.String())
-				w.Write(returnData)
-			} else {
-				w.Write([]byte("Succes"))
-			}
-
-		} else {
-			w.Write(nil)
+		w.Write(nil)
+	}))
+
+	return Connection{
+		AccessToken: "TEST",
+		testURL:     fmt.Sprintf("%s/devices", server.URL),
+	}, server
+}
+
+// testWrite is the last write made to a test server
+type testWrite struct {
+	path string
+	body string
+}
+
+// createTestWriteConnection creates a connection to a server that responds to reads with
+// read(url), or the test data if read is nil, and to writes with the values that were
+// applied. The path and body of the last write are recorded in the returned testWrite.
+func createTestWriteConnection(read func(url string) []byte) (Connection, *httptest.Server, *testWrite) {
+	if read == nil {
+		read = generateTestData
+	}
+
+	write := &testWrite{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write(read(r.URL.String()))
+			return
 		}
+
+		data, _ := ioutil.ReadAll(r.Body)
+		write.path, write.body = r.URL.Path, string(data)
+		w.Write(data)
 	}))
 
 	return Connection{
 		AccessToken: "TEST",
 		testURL:     fmt.Sprintf("%s/devices", server.URL),
+	}, server, write
+}
+
+func createTestOAuthConfig() (OAuthConfig, *httptest.Server) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/oauth2/access_token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if r.FormValue("client_id") != "client" || r.FormValue("client_secret") != "secret" ||
+			r.FormValue("grant_type") != "authorization_code" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"oauth2_error","error_description":"client not found"}`))
+			return
+		}
+
+		if r.FormValue("code") != "PIN123" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"oauth2_error","error_description":"authorization code not found"}`))
+			return
+		}
+
+		w.Write([]byte(`{"access_token":"c.TEST","expires_in":315360000}`))
+	}))
+
+	return OAuthConfig{
+		ClientID:     "client",
+		ClientSecret: "secret",
+		testURL:      server.URL,
 	}, server
 }
 
+func parseTestTime(str string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, str)
+	if err != nil {
+		fmt.Println("ERR: ", err)
+	}
+
+	return t
+}
+
 func generateTestData(url string) []byte {
 	returnData := []byte{}
 	var err error
 
 	switch url {
+	case "/":
+		data := map[string]interface{}{
+			"devices": map[string]json.RawMessage{
+				"thermostats":     generateTestData("/devices/thermostats"),
+				"smoke_co_alarms": generateTestData("/devices/smoke_co_alarms"),
+				"cameras":         generateTestData("/devices/cameras"),
+			},
+			"structures": json.RawMessage(generateTestData("/devices/structures")),
+			"metadata":   json.RawMessage(generateTestData("/metadata")),
+		}
+
+		returnData, err = json.Marshal(data)
+		if err != nil {
+			fmt.Println("ERR: ", err)
+		}
+	case "/metadata":
+		returnData = []byte(`{"access_token":"TEST","client_version":1}`)
 	case "/devices/thermostats":
 		data := thermostatTestData{
 			Abc: Thermostat{
@@ -89,7 +175,7 @@ func generateTestData(url string) []byte {
 				SunlightCorrectionEnabled: true,
 				StructureID:               "abc123",
 				FanTimerActive:            false,
-				FanTimerTimeout:           "1970-01-01T00:00:00.000Z",
+				FanTimerTimeout:           parseTestTime("1970-01-01T00:00:00.000Z"),
 				FanTimerDuration:          15,
 				PreviousHVACMode:          "",
 				HVACMode:                  "heat-cool",
@@ -99,7 +185,7 @@ func generateTestData(url string) []byte {
 				Label:                     "",
 				NameLong:                  "test thermostat",
 				IsOnline:                  true,
-				LastConnection:            "2019-01-02T14:27:53.729Z",
+				LastConnection:            parseTestTime("2019-01-02T14:27:53.729Z"),
 				HVACState:                 "off",
 			},
 		}
@@ -147,7 +233,7 @@ func generateTestData(url string) []byte {
 			SunlightCorrectionEnabled: true,
 			StructureID:               "abc123",
 			FanTimerActive:            false,
-			FanTimerTimeout:           "1970-01-01T00:00:00.000Z",
+			FanTimerTimeout:           parseTestTime("1970-01-01T00:00:00.000Z"),
 			FanTimerDuration:          15,
 			PreviousHVACMode:          "",
 			HVACMode:                  "heat-cool",
@@ -157,26 +243,51 @@ func generateTestData(url string) []byte {
 			Label:                     "",
 			NameLong:                  "test thermostat",
 			IsOnline:                  true,
-			LastConnection:            "2019-01-02T14:27:53.729Z",
+			LastConnection:            parseTestTime("2019-01-02T14:27:53.729Z"),
 			HVACState:                 "off",
 		}
 
+		returnData, err = json.Marshal(data)
+		if err != nil {
+			fmt.Println("ERR: ", err)
+		}
+	case "/devices/thermostats/def":
+		data := Thermostat{
+			Locale:             "en-US",
+			TemperatureScale:   "F",
+			DeviceID:           "def",
+			Name:               "locked thermostat",
+			CanHeat:            true,
+			CanCool:            false,
+			TargetTemperatureC: 21,
+			TargetTemperatureF: 70,
+			IsLocked:           true,
+			LockedTempMinC:     20,
+			LockedTempMinF:     68,
+			LockedTempMaxC:     22,
+			LockedTempMaxF:     72,
+			StructureID:        "abc123",
+			HVACMode:           "heat",
+			IsOnline:           true,
+			HVACState:          "heating",
+		}
+
 		returnData, err = json.Marshal(data)
 		if err != nil {
 			fmt.Println("ERR: ", err)
 		}
 	case "/devices/thermostats/abc/locale":
-		returnData = []byte("en-US")
+		returnData = []byte("\"en-US\"")
 	case "/devices/thermostats/abc/software_version":
-		returnData = []byte("1.0")
+		returnData = []byte("\"1.0\"")
 	case "/devices/thermostats/abc/name":
-		returnData = []byte("test thermostat")
+		returnData = []byte("\"test thermostat\"")
 	case "/devices/thermostats/abc/last_connection":
-		returnData = []byte("2016-12-31T23:59:59.000Z")
+		returnData = []byte("\"2016-12-31T23:59:59.000Z\"")
 	case "/devices/thermostats/abc/is_online":
 		returnData = []byte("true")
 	case "/devices/thermostats/abc/temperature_scale", "/devices/thermostats/def/temperature_scale":
-		returnData = []byte("F")
+		returnData = []byte("\"F\"")
 	case "/devices/thermostats/abc/target_temperature_f":
 		returnData = []byte("68")
 	case "/devices/thermostats/abc/target_temperature_high_f":
@@ -184,9 +295,11 @@ func generateTestData(url string) []byte {
 	case "/devices/thermostats/abc/target_temperature_low_f":
 		returnData = []byte("70")
 	case "/devices/thermostats/abc/hvac_mode":
-		returnData = []byte("heat-cool")
+		returnData = []byte("\"heat-cool\"")
 	case "/devices/thermostats/abc/label":
-		returnData = []byte("test thermostat label")
+		returnData = []byte("\"test thermostat label\"")
+	case "/devices/thermostats/abc/structure_id", "/devices/thermostats/def/structure_id", "/devices/cameras/abc/structure_id":
+		returnData = []byte("\"abc123\"")
 	case "/devices/smoke_co_alarms":
 		data := smokeCOAlarmTestData{
 			Abc: SmokeCOAlarm{
@@ -196,13 +309,13 @@ func generateTestData(url string) []byte {
 				StructureID:        "123",
 				Name:               "test smoke alarm",
 				NameLong:           "test smoke/co alarm",
-				LastConnection:     "2019-01-02T14:27:53.729Z",
+				LastConnection:     parseTestTime("2019-01-02T14:27:53.729Z"),
 				IsOnline:           true,
 				BatteryHealth:      "ok",
 				COAlarmState:       "ok",
 				SmokeAlarmState:    "ok",
 				IsManualTestActive: true,
-				LastManualTestTime: "2019-01-01T14:27:53.729Z",
+				LastManualTestTime: parseTestTime("2019-01-01T14:27:53.729Z"),
 				UIColorState:       "green",
 				WhereID:            "qwerty",
 				WhereName:          "Kitchen",
@@ -221,13 +334,13 @@ func generateTestData(url string) []byte {
 			StructureID:        "123",
 			Name:               "test smoke alarm",
 			NameLong:           "test smoke/co alarm",
-			LastConnection:     "2019-01-02T14:27:53.729Z",
+			LastConnection:     parseTestTime("2019-01-02T14:27:53.729Z"),
 			IsOnline:           true,
 			BatteryHealth:      "ok",
 			COAlarmState:       "ok",
 			SmokeAlarmState:    "ok",
 			IsManualTestActive: true,
-			LastManualTestTime: "2019-01-01T14:27:53.729Z",
+			LastManualTestTime: parseTestTime("2019-01-01T14:27:53.729Z"),
 			UIColorState:       "green",
 			WhereID:            "qwerty",
 			WhereName:          "Kitchen",
@@ -238,21 +351,21 @@ func generateTestData(url string) []byte {
 			fmt.Println("ERR: ", err)
 		}
 	case "/devices/smoke_co_alarms/abc/locale":
-		returnData = []byte("en-US")
+		returnData = []byte("\"en-US\"")
 	case "/devices/smoke_co_alarms/abc/software_version":
-		returnData = []byte("1.0")
+		returnData = []byte("\"1.0\"")
 	case "/devices/smoke_co_alarms/abc/name":
-		returnData = []byte("test smoke alarm")
+		returnData = []byte("\"test smoke alarm\"")
 	case "/devices/smoke_co_alarms/abc/last_connection":
-		returnData = []byte("2019-01-02T14:27:53.729Z")
+		returnData = []byte("\"2019-01-02T14:27:53.729Z\"")
 	case "/devices/smoke_co_alarms/abc/is_online":
 		returnData = []byte("true")
 	case "/devices/smoke_co_alarms/abc/battery_health":
-		returnData = []byte("ok")
+		returnData = []byte("\"ok\"")
 	case "/devices/smoke_co_alarms/abc/co_alarm_state":
-		returnData = []byte("ok")
+		returnData = []byte("\"ok\"")
 	case "/devices/smoke_co_alarms/abc/smoke_alarm_state":
-		returnData = []byte("ok")
+		returnData = []byte("\"ok\"")
 	case "/devices/cameras":
 		data := cameraTestData{
 			Abc: Camera{
@@ -266,7 +379,7 @@ func generateTestData(url string) []byte {
 				IsOnline:              true,
 				IsStreaming:           true,
 				IsAudioInputEnabled:   true,
-				LastIsOnlineChange:    "2016-12-29T18:42:00.000Z",
+				LastIsOnlineChange:    parseTestTime("2016-12-29T18:42:00.000Z"),
 				IsVideoHistoryEnabled: true,
 				WebURL:                "https://home.nest.com/cameras/abc?auth=camera_token",
 				AppURL:                "nestmobile://cameras/abc?auth=camera_token",
@@ -290,7 +403,7 @@ func generateTestData(url string) []byte {
 			IsOnline:              true,
 			IsStreaming:           true,
 			IsAudioInputEnabled:   true,
-			LastIsOnlineChange:    "2016-12-29T18:42:00.000Z",
+			LastIsOnlineChange:    parseTestTime("2016-12-29T18:42:00.000Z"),
 			IsVideoHistoryEnabled: true,
 			WebURL:                "https://home.nest.com/cameras/abc?auth=camera_token",
 			AppURL:                "nestmobile://cameras/abc?auth=camera_token",
@@ -302,9 +415,9 @@ func generateTestData(url string) []byte {
 			fmt.Println("ERR: ", err)
 		}
 	case "/devices/cameras/abc/software_version":
-		returnData = []byte("1.0")
+		returnData = []byte("\"1.0\"")
 	case "/devices/cameras/abc/name":
-		returnData = []byte("test camera")
+		returnData = []byte("\"test camera\"")
 	case "/devices/cameras/abc/is_online":
 		returnData = []byte("true")
 	case "/devices/cameras/abc/is_streaming":
@@ -314,11 +427,17 @@ func generateTestData(url string) []byte {
 	case "/devices/cameras/abc/is_video_history_enabled":
 		returnData = []byte("true")
 	case "/devices/cameras/abc/web_url":
-		returnData = []byte("https://home.nest.com/cameras/abc?auth=camera_token")
+		returnData = []byte("\"https://home.nest.com/cameras/abc?auth=camera_token\"")
 	case "/devices/cameras/abc/app_url":
-		returnData = []byte("nestmobile://cameras/abc?auth=camera_token")
+		returnData = []byte("\"nestmobile://cameras/abc?auth=camera_token\"")
+	case "/devices/cameras/abc/is_public_share_enabled":
+		returnData = []byte("true")
+	case "/devices/cameras/abc/public_share_url":
+		returnData = []byte("\"https://video.nest.com/live/abc\"")
+	case "/devices/cameras/abc/activity_zones":
+		returnData = []byte(`[{"name":"Driveway","id":1}]`)
 	case "/devices/cameras/abc/last_event":
-		returnData = []byte("event")
+		returnData = []byte(`{"has_motion":true,"start_time":"2016-12-29T00:00:00.000Z"}`)
 	case "/devices/structures":
 		data := structureTestData{
 			Abc: Structure{
@@ -330,11 +449,11 @@ func generateTestData(url string) []byte {
 				Name:                "test structure",
 				CountryCode:         "US",
 				PostalCode:          "12345",
-				PeakPeriodStartTime: "2016-12-29T18:42:00.000Z",
-				PeakPeriodEndTime:   "2016-12-39T18:42:00.000Z",
+				PeakPeriodStartTime: parseTestTime("2016-12-29T18:42:00.000Z"),
+				PeakPeriodEndTime:   parseTestTime("2016-12-30T18:42:00.000Z"),
 				TimeZone:            "America/Chicago",
 				ETA:                 []string{"trip1"},
-				ETABegin:            "2016-12-29T18:42:00.000Z",
+				ETABegin:            parseTestTime("2016-12-29T18:42:00.000Z"),
 				RHREnrollment:       true,
 				WWNSecurityState:    "ok",
 				COAlarmState:        "ok",
@@ -356,11 +475,11 @@ func generateTestData(url string) []byte {
 			Name:                "test structure",
 			CountryCode:         "US",
 			PostalCode:          "12345",
-			PeakPeriodStartTime: "2016-12-29T18:42:00.000Z",
-			PeakPeriodEndTime:   "2016-12-39T18:42:00.000Z",
+			PeakPeriodStartTime: parseTestTime("2016-12-29T18:42:00.000Z"),
+			PeakPeriodEndTime:   parseTestTime("2016-12-30T18:42:00.000Z"),
 			TimeZone:            "America/Chicago",
 			ETA:                 []string{"trip1"},
-			ETABegin:            "2016-12-29T18:42:00.000Z",
+			ETABegin:            parseTestTime("2016-12-29T18:42:00.000Z"),
 			RHREnrollment:       true,
 			WWNSecurityState:    "ok",
 			COAlarmState:        "ok",
@@ -378,9 +497,9 @@ func generateTestData(url string) []byte {
 	case "/devices/structures/abc/cameras":
 		returnData = []byte("[\"789\"]")
 	case "/devices/structures/abc/away":
-		returnData = []byte("home")
+		returnData = []byte("\"home\"")
 	case "/devices/structures/abc/name":
-		returnData = []byte("test structure")
+		returnData = []byte("\"test structure\"")
 	}
 
 	return returnData
@@ -5,59 +5,83 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Thermostat contains all the data for an individual Nest thermostat
 type Thermostat struct {
-	Humidity                  int     `json:"humidity"`
-	Locale                    string  `json:"locale"`
-	TemperatureScale          string  `json:"temperature_scale"`
-	IsUsingEmergencyHeat      bool    `json:"is_using_emergency_heat"`
-	HasFan                    bool    `json:"has_fan"`
-	SoftwareVersion           string  `json:"software_version"`
-	HasLeaf                   bool    `json:"has_leaf"`
-	WhereID                   string  `json:"where_id"`
-	DeviceID                  string  `json:"device_id"`
-	Name                      string  `json:"name"`
-	CanHeat                   bool    `json:"can_heat"`
-	CanCool                   bool    `json:"can_cool"`
-	TargetTemperatureC        float32 `json:"target_temperature_c"`
-	TargetTemperatureF        int     `json:"target_temperature_f"`
-	TargetTemperatureHighC    float32 `json:"target_temperature_high_c"`
-	TargetTemperatureHighF    int     `json:"target_temperature_high_f"`
-	TargetTemperatureLowC     float32 `json:"target_temperature_low_c"`
-	TargetTemperatureLowF     int     `json:"target_temperature_low_f"`
-	AmbientTemperatureC       float32 `json:"ambient_temperature_c"`
-	AmbientTemperatureF       int     `json:"ambient_temperature_f"`
-	AwayTemperatureHighC      float32 `json:"away_temperature_high_c"`
-	AwayTemperatureHighF      int     `json:"away_temperature_high_f"`
-	AwayTemperatureLowC       float32 `json:"away_temperature_low_c"`
-	AwayTemperatureLowF       int     `json:"away_temperature_low_f"`
-	EcoTemperatureHighC       float32 `json:"eco_temperature_high_c"`
-	EcoTemperatureHighF       int     `json:"eco_temperature_high_f"`
-	EcoTemperatureLowC        float32 `json:"eco_temperature_low_c"`
-	EcoTemperatureLowF        int     `json:"eco_temperature_low_f"`
-	IsLocked                  bool    `json:"is_locked"`
-	LockedTempMinC            float32 `json:"locked_temp_min_c"`
-	LockedTempMinF            int     `json:"locked_temp_min_f"`
-	LockedTempMaxC            float32 `json:"locked_temp_max_c"`
-	LockedTempMaxF            int     `json:"locked_temp_max_f"`
-	SunlightCorrectionActive  bool    `json:"sunlight_correction_active"`
-	SunlightCorrectionEnabled bool    `json:"sunlight_correction_enabled"`
-	StructureID               string  `json:"structure_id"`
-	FanTimerActive            bool    `json:"fan_timer_active"`
-	FanTimerTimeout           string  `json:"fan_timer_timeout"`
-	FanTimerDuration          int     `json:"fan_timer_duration"`
-	PreviousHVACMode          string  `json:"previous_hvac_mode"`
-	HVACMode                  string  `json:"hvac_mode"`
-	TimeToTarget              string  `json:"time_to_target"`
-	TimeToTargetTraining      string  `json:"time_to_target_training"`
-	WhereName                 string  `json:"where_name"`
-	Label                     string  `json:"label"`
-	NameLong                  string  `json:"name_long"`
-	IsOnline                  bool    `json:"is_online"`
-	LastConnection            string  `json:"last_connection"`
-	HVACState                 string  `json:"hvac_state"`
+	Humidity                  int              `json:"humidity"`
+	Locale                    string           `json:"locale"`
+	TemperatureScale          TemperatureScale `json:"temperature_scale"`
+	IsUsingEmergencyHeat      bool             `json:"is_using_emergency_heat"`
+	HasFan                    bool             `json:"has_fan"`
+	SoftwareVersion           string           `json:"software_version"`
+	HasLeaf                   bool             `json:"has_leaf"`
+	WhereID                   string           `json:"where_id"`
+	DeviceID                  string           `json:"device_id"`
+	Name                      string           `json:"name"`
+	CanHeat                   bool             `json:"can_heat"`
+	CanCool                   bool             `json:"can_cool"`
+	TargetTemperatureC        float32          `json:"target_temperature_c"`
+	TargetTemperatureF        int              `json:"target_temperature_f"`
+	TargetTemperatureHighC    float32          `json:"target_temperature_high_c"`
+	TargetTemperatureHighF    int              `json:"target_temperature_high_f"`
+	TargetTemperatureLowC     float32          `json:"target_temperature_low_c"`
+	TargetTemperatureLowF     int              `json:"target_temperature_low_f"`
+	AmbientTemperatureC       float32          `json:"ambient_temperature_c"`
+	AmbientTemperatureF       int              `json:"ambient_temperature_f"`
+	AwayTemperatureHighC      float32          `json:"away_temperature_high_c"`
+	AwayTemperatureHighF      int              `json:"away_temperature_high_f"`
+	AwayTemperatureLowC       float32          `json:"away_temperature_low_c"`
+	AwayTemperatureLowF       int              `json:"away_temperature_low_f"`
+	EcoTemperatureHighC       float32          `json:"eco_temperature_high_c"`
+	EcoTemperatureHighF       int              `json:"eco_temperature_high_f"`
+	EcoTemperatureLowC        float32          `json:"eco_temperature_low_c"`
+	EcoTemperatureLowF        int              `json:"eco_temperature_low_f"`
+	IsLocked                  bool             `json:"is_locked"`
+	LockedTempMinC            float32          `json:"locked_temp_min_c"`
+	LockedTempMinF            int              `json:"locked_temp_min_f"`
+	LockedTempMaxC            float32          `json:"locked_temp_max_c"`
+	LockedTempMaxF            int              `json:"locked_temp_max_f"`
+	SunlightCorrectionActive  bool             `json:"sunlight_correction_active"`
+	SunlightCorrectionEnabled bool             `json:"sunlight_correction_enabled"`
+	StructureID               string           `json:"structure_id"`
+	FanTimerActive            bool             `json:"fan_timer_active"`
+	FanTimerTimeout           time.Time        `json:"fan_timer_timeout"`
+	FanTimerDuration          int              `json:"fan_timer_duration"`
+	PreviousHVACMode          HVACMode         `json:"previous_hvac_mode"`
+	HVACMode                  HVACMode         `json:"hvac_mode"`
+	TimeToTarget              string           `json:"time_to_target"`
+	TimeToTargetTraining      string           `json:"time_to_target_training"`
+	WhereName                 string           `json:"where_name"`
+	Label                     string           `json:"label"`
+	NameLong                  string           `json:"name_long"`
+	IsOnline                  bool             `json:"is_online"`
+	LastConnection            time.Time        `json:"last_connection"`
+	HVACState                 HVACState        `json:"hvac_state"`
+}
+
+// UnmarshalJSON decodes a thermostat, leaving timestamps that are empty as the zero time
+func (t *Thermostat) UnmarshalJSON(data []byte) error {
+	type thermostat Thermostat
+
+	aux := struct {
+		*thermostat
+		FanTimerTimeout timestamp `json:"fan_timer_timeout"`
+		LastConnection  timestamp `json:"last_connection"`
+	}{
+		thermostat: (*thermostat)(t),
+	}
+
+	err := json.Unmarshal(data, &aux)
+	if err != nil {
+		return err
+	}
+
+	t.FanTimerTimeout = aux.FanTimerTimeout.Time
+	t.LastConnection = aux.LastConnection.Time
+
+	return nil
 }
 
 // GetThermostats returns all Nest thermostats along with all their data
@@ -134,272 +158,369 @@ func (n *Connection) GetThermostat(deviceID string) (Thermostat, error) {
 
 // GetThermostatLocale returns the locale of the specified thermostat
 func (n *Connection) GetThermostatLocale(deviceID string) (string, error) {
-	return n.getValue("thermostats", deviceID, "locale")
+	var val string
+	err := n.getValue("thermostats", deviceID, "locale", &val)
+
+	return val, err
 }
 
 // GetThermostatSoftwareVersion returns the software version of the specified thermostat
 func (n *Connection) GetThermostatSoftwareVersion(deviceID string) (string, error) {
-	return n.getValue("thermostats", deviceID, "software_version")
+	var val string
+	err := n.getValue("thermostats", deviceID, "software_version", &val)
+
+	return val, err
 }
 
 // GetThermostatName returns the name of the specified thermostat
 func (n *Connection) GetThermostatName(deviceID string) (string, error) {
-	return n.getValue("thermostats", deviceID, "name")
+	var val string
+	err := n.getValue("thermostats", deviceID, "name", &val)
+
+	return val, err
 }
 
 // GetThermostatLastConnection gets the last connection time of the specified thermostat
-func (n *Connection) GetThermostatLastConnection(deviceID string) (string, error) {
-	return n.getValue("thermostats", deviceID, "last_connection")
+func (n *Connection) GetThermostatLastConnection(deviceID string) (time.Time, error) {
+	var val timestamp
+	err := n.getValue("thermostats", deviceID, "last_connection", &val)
+
+	return val.Time, err
 }
 
 // IsThermostatOnline returns true if the specified thermostat is online, false if it isn't
-func (n *Connection) IsThermostatOnline(deviceID string) (string, error) {
-	return n.getValue("thermostats", deviceID, "is_online")
+func (n *Connection) IsThermostatOnline(deviceID string) (bool, error) {
+	var val bool
+	err := n.getValue("thermostats", deviceID, "is_online", &val)
+
+	return val, err
 }
 
 // GetTemperatureScale returns the temperature scale of the specified thermostat
-func (n *Connection) GetTemperatureScale(deviceID string) (string, error) {
-	return n.getValue("thermostats", deviceID, "temperature_scale")
+func (n *Connection) GetTemperatureScale(deviceID string) (TemperatureScale, error) {
+	var val TemperatureScale
+	err := n.getValue("thermostats", deviceID, "temperature_scale", &val)
+
+	return val, err
 }
 
-// GetTargetTemperature returns the target temperature of the specified thermostat
-func (n *Connection) GetTargetTemperature(deviceID string) (string, error) {
+// GetTargetTemperature returns the target temperature of the specified thermostat,
+// in its temperature scale
+func (n *Connection) GetTargetTemperature(deviceID string) (float64, error) {
 	scale, err := n.GetTemperatureScale(deviceID)
 	if err != nil {
-		return "", err
+		return 0, err
 	}
 
-	scale = strings.ToLower(strings.Replace(scale, "\"", "", -1))
+	var temp float64
+	err = n.getValue("thermostats", deviceID, fmt.Sprintf("target_temperature_%s", strings.ToLower(scale.String())), &temp)
+	if err != nil {
+		return 0, err
+	}
 
-	return n.getValue("thermostats", deviceID, fmt.Sprintf("target_temperature_%s", scale))
+	return temp, nil
 }
 
-// GetTargetHighLowTemperature returns the target high and low temperatures of the specified thermostat
-func (n *Connection) GetTargetHighLowTemperature(deviceID string) (string, string, error) {
+// GetTargetHighLowTemperature returns the target high and low temperatures of the
+// specified thermostat, in its temperature scale
+func (n *Connection) GetTargetHighLowTemperature(deviceID string) (float64, float64, error) {
 	scale, err := n.GetTemperatureScale(deviceID)
 	if err != nil {
-		return "", "", err
+		return 0, 0, err
 	}
 
-	scale = strings.ToLower(strings.Replace(scale, "\"", "", -1))
+	suffix := strings.ToLower(scale.String())
 
-	high, err := n.getValue("thermostats", deviceID, fmt.Sprintf("target_temperature_high_%s", scale))
+	var high, low float64
+
+	err = n.getValue("thermostats", deviceID, fmt.Sprintf("target_temperature_high_%s", suffix), &high)
 	if err != nil {
-		return "", "", err
+		return 0, 0, err
 	}
 
-	low, err := n.getValue("thermostats", deviceID, fmt.Sprintf("target_temperature_low_%s", scale))
+	err = n.getValue("thermostats", deviceID, fmt.Sprintf("target_temperature_low_%s", suffix), &low)
 	if err != nil {
-		return "", "", err
+		return 0, 0, err
 	}
 
 	return high, low, nil
 }
 
 // GetHVACMode returns the hvac mode of the specified thermostat
-func (n *Connection) GetHVACMode(deviceID string) (string, error) {
-	return n.getValue("thermostats", deviceID, "hvac_mode")
+func (n *Connection) GetHVACMode(deviceID string) (HVACMode, error) {
+	var val HVACMode
+	err := n.getValue("thermostats", deviceID, "hvac_mode", &val)
+
+	return val, err
 }
 
 // GetThermostatLabel returns the lebl of the specified thermostat
 func (n *Connection) GetThermostatLabel(deviceID string) (string, error) {
-	return n.getValue("thermostats", deviceID, "label")
+	var val string
+	err := n.getValue("thermostats", deviceID, "label", &val)
+
+	return val, err
 }
 
-// SetTemperatureScale sets the temperature scale of the specified thermostat
-func (n *Connection) SetTemperatureScale(deviceID, scale string) error {
+// SetTemperatureScale sets the temperature scale of the specified thermostat and returns
+// the scale Nest applied
+func (n *Connection) SetTemperatureScale(deviceID string, scale TemperatureScale) (TemperatureScale, error) {
 	// Error checking
-	scale = strings.Trim(scale, " ")
-	validVals := []string{"F", "C"}
+	scale = TemperatureScale(strings.Trim(scale.String(), " "))
 
 	if scale == "" {
-		return errors.New("Scale must not be empty")
+		return "", errors.New("Scale must not be empty")
 	}
 
-	valid := false
-	for _, v := range validVals {
-		if scale == v {
-			valid = true
-		}
+	err := scale.Validate()
+	if err != nil {
+		return "", err
 	}
 
-	if !valid {
-		return fmt.Errorf("Temperature Scale must be one of the following: %s", validVals)
-	}
+	confirmed := thermostatRequest{}
 
-	vals := make(map[string]interface{})
-	vals["temperature_scale"] = scale
+	err = n.setValue("thermostats", deviceID, thermostatRequest{TemperatureScale: scale}, &confirmed)
+	if err != nil {
+		return "", err
+	}
 
-	return n.setValue("thermostats", deviceID, vals)
+	return confirmed.TemperatureScale, nil
 }
 
 // SetTargetTemperatureF changes the target temperature (F) of the specified thermostat
-func (n *Connection) SetTargetTemperatureF(deviceID string, temp int) error {
+// and returns the temperature Nest applied
+func (n *Connection) SetTargetTemperatureF(deviceID string, temp int) (int, error) {
 	// Error checking
 	if temp < 50 || temp > 90 {
-		return errors.New("Target Temperature must be in the range of 50 - 90")
+		return 0, errors.New("Target Temperature must be in the range of 50 - 90")
 	}
 
-	scale, err := n.GetTemperatureScale(deviceID)
+	thermostat, err := n.GetThermostat(deviceID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if scale != "F" {
-		return errors.New("Temperature Scale must be set to F")
+	if thermostat.TemperatureScale != TemperatureScaleF {
+		return 0, errors.New("Temperature Scale must be set to F")
 	}
 
-	vals := make(map[string]interface{})
-	vals["target_temperature_f"] = temp
+	err = thermostat.ValidateTargetTemperature(Fahrenheit(float64(temp)))
+	if err != nil {
+		return 0, err
+	}
+
+	req := thermostatRequest{}
+	req.setTargetTemperature(Fahrenheit(float64(temp)))
 
-	return n.setValue("thermostats", deviceID, vals)
+	confirmed := thermostatRequest{}
+
+	err = n.setValue("thermostats", deviceID, req, &confirmed)
+	if err != nil {
+		return 0, err
+	}
+
+	return *confirmed.TargetTemperatureF, nil
 }
 
 // SetTargetTemperatureC changes the target temperature (C) of the specified thermostat
-func (n *Connection) SetTargetTemperatureC(deviceID string, temp float32) error {
+// and returns the temperature Nest applied
+func (n *Connection) SetTargetTemperatureC(deviceID string, temp float32) (float32, error) {
 	// Error checking
 	if temp < 9 || temp > 32 {
-		return errors.New("Target Temperature must be in the range of 9 - 32")
+		return 0, errors.New("Target Temperature must be in the range of 9 - 32")
 	}
 
-	scale, err := n.GetTemperatureScale(deviceID)
+	thermostat, err := n.GetThermostat(deviceID)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if scale != "C" {
-		return errors.New("Temperature Scale must be set to C")
+	if thermostat.TemperatureScale != TemperatureScaleC {
+		return 0, errors.New("Temperature Scale must be set to C")
+	}
+
+	err = thermostat.ValidateTargetTemperature(Celsius(float64(temp)))
+	if err != nil {
+		return 0, err
 	}
 
-	vals := make(map[string]interface{})
-	vals["target_temperature_c"] = temp
+	req := thermostatRequest{}
+	req.setTargetTemperature(Celsius(float64(temp)))
 
-	return n.setValue("thermostats", deviceID, vals)
+	confirmed := thermostatRequest{}
+
+	err = n.setValue("thermostats", deviceID, req, &confirmed)
+	if err != nil {
+		return 0, err
+	}
+
+	return float32(*confirmed.TargetTemperatureC), nil
 }
 
 // SetTargetHighLowTemperatureF changes the target high and low temperatures (F) of
-// the specified thermostat
-func (n *Connection) SetTargetHighLowTemperatureF(deviceID string, high, low int) error {
+// the specified thermostat and returns the temperatures Nest applied
+func (n *Connection) SetTargetHighLowTemperatureF(deviceID string, high, low int) (int, int, error) {
 	// Error checking
 	if high < 50 || high > 90 {
-		return errors.New("Target High Temperature must be in the range of 50 - 90")
+		return 0, 0, errors.New("Target High Temperature must be in the range of 50 - 90")
 	}
 
 	if low < 50 || low > 90 {
-		return errors.New("Target Low Temperature must be in the range of 50 - 90")
+		return 0, 0, errors.New("Target Low Temperature must be in the range of 50 - 90")
 	}
 
-	scale, err := n.GetTemperatureScale(deviceID)
+	thermostat, err := n.GetThermostat(deviceID)
 	if err != nil {
-		return err
+		return 0, 0, err
 	}
 
-	if scale != "F" {
-		return errors.New("Temperature Scale must be set to F")
+	if thermostat.TemperatureScale != TemperatureScaleF {
+		return 0, 0, errors.New("Temperature Scale must be set to F")
 	}
 
-	vals := make(map[string]interface{})
-	vals["target_temperature_high_f"] = high
-	vals["target_temperature_low_f"] = low
+	err = thermostat.ValidateTargetRange(Fahrenheit(float64(low)), Fahrenheit(float64(high)))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	req := thermostatRequest{}
+	req.setTargetRange(Fahrenheit(float64(low)), Fahrenheit(float64(high)))
+
+	confirmed := thermostatRequest{}
+
+	err = n.setValue("thermostats", deviceID, req, &confirmed)
+	if err != nil {
+		return 0, 0, err
+	}
 
-	return n.setValue("thermostats", deviceID, vals)
+	return *confirmed.TargetTemperatureHighF, *confirmed.TargetTemperatureLowF, nil
 }
 
 // SetTargetHighLowTemperatureC changes the target high and low temperatures (C) of
-// the specified thermostat
-func (n *Connection) SetTargetHighLowTemperatureC(deviceID string, high, low float32) error {
+// the specified thermostat and returns the temperatures Nest applied
+func (n *Connection) SetTargetHighLowTemperatureC(deviceID string, high, low float32) (float32, float32, error) {
 	// Error checking
 	if high < 9 || high > 32 {
-		return errors.New("Target High Temperature must be in the range of 9 - 32")
+		return 0, 0, errors.New("Target High Temperature must be in the range of 9 - 32")
 	}
 
 	if low < 9 || low > 32 {
-		return errors.New("Target Low Temperature must be in the range of 9 - 32")
+		return 0, 0, errors.New("Target Low Temperature must be in the range of 9 - 32")
 	}
 
-	scale, err := n.GetTemperatureScale(deviceID)
+	thermostat, err := n.GetThermostat(deviceID)
 	if err != nil {
-		return err
+		return 0, 0, err
+	}
+
+	if thermostat.TemperatureScale != TemperatureScaleC {
+		return 0, 0, errors.New("Temperature Scale must be set to C")
 	}
 
-	if scale != "C" {
-		return errors.New("Temperature Scale must be set to C")
+	err = thermostat.ValidateTargetRange(Celsius(float64(low)), Celsius(float64(high)))
+	if err != nil {
+		return 0, 0, err
 	}
 
-	vals := make(map[string]interface{})
-	vals["target_temperature_high_c"] = high
-	vals["target_temperature_low_c"] = low
+	req := thermostatRequest{}
+	req.setTargetRange(Celsius(float64(low)), Celsius(float64(high)))
+
+	confirmed := thermostatRequest{}
 
-	return n.setValue("thermostats", deviceID, vals)
+	err = n.setValue("thermostats", deviceID, req, &confirmed)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return float32(*confirmed.TargetTemperatureHighC), float32(*confirmed.TargetTemperatureLowC), nil
 }
 
-// SetHVACMode changes the HVAC mode of the specified thermostat
-func (n *Connection) SetHVACMode(deviceID, mode string) error {
+// SetHVACMode changes the HVAC mode of the specified thermostat and returns the mode
+// Nest applied
+func (n *Connection) SetHVACMode(deviceID string, mode HVACMode) (HVACMode, error) {
 	// Error checking
-	mode = strings.Trim(mode, " ")
-	validVals := []string{"heat", "cool", "heat-cool", "eco", "off"}
+	mode = HVACMode(strings.Trim(mode.String(), " "))
 
-	if mode == "" {
-		return errors.New("HVAC Mode must not be empty")
+	err := mode.Validate()
+	if err != nil {
+		return "", err
 	}
 
-	valid := false
-	for _, v := range validVals {
-		if mode == v {
-			valid = true
-		}
-	}
+	confirmed := thermostatRequest{}
 
-	if !valid {
-		return fmt.Errorf("HVAC Mode must be one of the following: %s", validVals)
+	err = n.setValue("thermostats", deviceID, thermostatRequest{HVACMode: mode}, &confirmed)
+	if err != nil {
+		return "", err
 	}
 
-	vals := make(map[string]interface{})
-	vals["hvac_mode"] = mode
-
-	return n.setValue("thermostats", deviceID, vals)
+	return confirmed.HVACMode, nil
 }
 
-// SetThermostatLabel - label
-func (n *Connection) SetThermostatLabel(deviceID, label string) error {
+// SetThermostatLabel - label, returns the label Nest applied
+func (n *Connection) SetThermostatLabel(deviceID, label string) (string, error) {
 	// Error checking
 	if strings.Trim(label, " ") == "" {
-		return errors.New("Label must not be empty")
+		return "", errors.New("Label must not be empty")
 	}
 
-	vals := make(map[string]interface{})
-	vals["label"] = label
+	confirmed := thermostatRequest{}
 
-	return n.setValue("thermostats", deviceID, vals)
+	err := n.setValue("thermostats", deviceID, thermostatRequest{Label: label}, &confirmed)
+	if err != nil {
+		return "", err
+	}
+
+	return confirmed.Label, nil
 }
 
-// TurnOnFanTimer turns on the fan timer and sets the duration of the specified thermostat
-func (n *Connection) TurnOnFanTimer(deviceID string, duration int) error {
+// TurnOnFanTimer turns on the fan timer and sets the duration of the specified thermostat,
+// returning the duration Nest applied
+func (n *Connection) TurnOnFanTimer(deviceID string, duration int) (int, error) {
 	// Error checking
-	validVals := []int{15, 30, 45, 60, 120, 240, 480, 720}
-
-	valid := false
-	for _, v := range validVals {
-		if duration == v {
-			valid = true
-		}
+	err := validateFanTimerDuration(duration)
+	if err != nil {
+		return 0, err
 	}
 
-	if !valid {
-		return fmt.Errorf("Fan Timer Duration must be one of the following: %d", validVals)
+	req := thermostatRequest{}
+	req.setFanTimer(duration)
+
+	confirmed := thermostatRequest{}
+
+	err = n.setValue("thermostats", deviceID, req, &confirmed)
+	if err != nil {
+		return 0, err
 	}
 
-	vals := make(map[string]interface{})
-	vals["fan_timer_active"] = true
-	vals["fan_timer_duration"] = duration
+	return confirmed.FanTimerDuration, nil
+}
+
+// TurnOffFanTimer turns off the fan timer of the specified thermostat and returns whether
+// Nest left it active
+func (n *Connection) TurnOffFanTimer(deviceID string) (bool, error) {
+	req := thermostatRequest{}
+	req.setFanTimer(0)
+
+	confirmed := thermostatRequest{}
+
+	err := n.setValue("thermostats", deviceID, req, &confirmed)
+	if err != nil {
+		return false, err
+	}
 
-	return n.setValue("thermostats", deviceID, vals)
+	return *confirmed.FanTimerActive, nil
 }
 
-// TurnOffFanTimer turns off the fant timer of the specified thermostat
-func (n *Connection) TurnOffFanTimer(deviceID string) error {
-	vals := make(map[string]interface{})
-	vals["fan_timer_active"] = false
+// validateFanTimerDuration returns an error unless duration is one of the fan timer
+// durations, in minutes, that Nest supports
+func validateFanTimerDuration(duration int) error {
+	validVals := []int{15, 30, 45, 60, 120, 240, 480, 720}
+
+	for _, v := range validVals {
+		if duration == v {
+			return nil
+		}
+	}
 
-	return n.setValue("thermostats", deviceID, vals)
+	return fmt.Errorf("Fan Timer Duration must be one of the following: %d", validVals)
 }
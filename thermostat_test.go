@@ -2,6 +2,7 @@ package nest
 
 import (
 	"testing"
+	"time"
 )
 
 func TestGetThermostats(t *testing.T) {
@@ -312,9 +313,9 @@ func TestGetThermostatLastConnection(t *testing.T) {
 		}
 
 		{
-			expected := "2016-12-31T23:59:59.000Z"
-			if lastConn != expected {
-				t.Fatalf("Expected Last Connection to equal %s, got %s", expected, lastConn)
+			expected := time.Date(2016, 12, 31, 23, 59, 59, 0, time.UTC)
+			if !lastConn.Equal(expected) {
+				t.Fatalf("Expected Last Connection to equal %v, got %v", expected, lastConn)
 			}
 		}
 	})
@@ -365,9 +366,9 @@ func TestIsThermostatOnline(t *testing.T) {
 		}
 
 		{
-			expected := "true"
+			expected := true
 			if isOnline != expected {
-				t.Fatalf("Expected Is Online to equal %s, got %s", expected, isOnline)
+				t.Fatalf("Expected Is Online to equal %t, got %t", expected, isOnline)
 			}
 		}
 	})
@@ -418,7 +419,7 @@ func TestGetTemperatureScale(t *testing.T) {
 		}
 
 		{
-			expected := "F"
+			expected := TemperatureScaleF
 			if tempScale != expected {
 				t.Fatalf("Expected Temperature Scale to equal %s, got %s", expected, tempScale)
 			}
@@ -471,9 +472,9 @@ func TestGetTargetTemperature(t *testing.T) {
 		}
 
 		{
-			expected := "68"
+			expected := 68.0
 			if temp != expected {
-				t.Fatalf("Expected Target Temperature to equal %s, got %s", expected, temp)
+				t.Fatalf("Expected Target Temperature to equal %v, got %v", expected, temp)
 			}
 		}
 	})
@@ -524,16 +525,16 @@ func TestGetTargetHighLowTemperature(t *testing.T) {
 		}
 
 		{
-			expected := "72"
+			expected := 72.0
 			if high != expected {
-				t.Fatalf("Expected Target Temperature High to equal %s, got %s", expected, high)
+				t.Fatalf("Expected Target Temperature High to equal %v, got %v", expected, high)
 			}
 		}
 
 		{
-			expected := "70"
+			expected := 70.0
 			if low != expected {
-				t.Fatalf("Expected Target Temperature Low to equal %s, got %s", expected, low)
+				t.Fatalf("Expected Target Temperature Low to equal %v, got %v", expected, low)
 			}
 		}
 	})
@@ -584,7 +585,7 @@ func TestGetHVACMode(t *testing.T) {
 		}
 
 		{
-			expected := "heat-cool"
+			expected := HVACModeHeatCool
 			if mode != expected {
 				t.Fatalf("Expected HVAC Mode to equal %s, got %s", expected, mode)
 			}
@@ -684,14 +685,14 @@ func TestSetTemperatureScale(t *testing.T) {
 	defer server.Close()
 
 	t.Run("Success", func(t *testing.T) {
-		err := n.SetTemperatureScale("abc", "F")
+		_, err := n.SetTemperatureScale("abc", "F")
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("Invalid device id", func(t *testing.T) {
-		err := n.SetTemperatureScale("", "F")
+		_, err := n.SetTemperatureScale("", "F")
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -705,7 +706,7 @@ func TestSetTemperatureScale(t *testing.T) {
 	})
 
 	t.Run("Invalid scale 1", func(t *testing.T) {
-		err := n.SetTemperatureScale("abc", "")
+		_, err := n.SetTemperatureScale("abc", "")
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -719,7 +720,7 @@ func TestSetTemperatureScale(t *testing.T) {
 	})
 
 	t.Run("Invalid scale 2", func(t *testing.T) {
-		err := n.SetTemperatureScale("abc", "A")
+		_, err := n.SetTemperatureScale("abc", "A")
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -738,14 +739,14 @@ func TestSetTargetTemperatureF(t *testing.T) {
 	defer server.Close()
 
 	t.Run("Success", func(t *testing.T) {
-		err := n.SetTargetTemperatureF("abc", 70)
+		_, err := n.SetTargetTemperatureF("def", 70)
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("Invalid device id", func(t *testing.T) {
-		err := n.SetTargetTemperatureF("", 70)
+		_, err := n.SetTargetTemperatureF("", 70)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -759,7 +760,7 @@ func TestSetTargetTemperatureF(t *testing.T) {
 	})
 
 	t.Run("Invalid temperature", func(t *testing.T) {
-		err := n.SetTargetTemperatureF("abc", 100)
+		_, err := n.SetTargetTemperatureF("abc", 100)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -778,7 +779,7 @@ func TestSetTargetTemperatureC(t *testing.T) {
 	defer server.Close()
 
 	t.Run("Invalid device id", func(t *testing.T) {
-		err := n.SetTargetTemperatureC("", 25)
+		_, err := n.SetTargetTemperatureC("", 25)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -792,7 +793,7 @@ func TestSetTargetTemperatureC(t *testing.T) {
 	})
 
 	t.Run("Invalid temperature", func(t *testing.T) {
-		err := n.SetTargetTemperatureC("abc", 50)
+		_, err := n.SetTargetTemperatureC("abc", 50)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -806,7 +807,7 @@ func TestSetTargetTemperatureC(t *testing.T) {
 	})
 
 	t.Run("Invalid scale", func(t *testing.T) {
-		err := n.SetTargetTemperatureC("abc", 30)
+		_, err := n.SetTargetTemperatureC("abc", 30)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -825,14 +826,14 @@ func TestSetTargetHighLowTemperatureF(t *testing.T) {
 	defer server.Close()
 
 	t.Run("Success", func(t *testing.T) {
-		err := n.SetTargetHighLowTemperatureF("abc", 70, 72)
+		_, _, err := n.SetTargetHighLowTemperatureF("abc", 74, 70)
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("Invalid device id", func(t *testing.T) {
-		err := n.SetTargetHighLowTemperatureF("", 70, 72)
+		_, _, err := n.SetTargetHighLowTemperatureF("", 70, 72)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -846,7 +847,7 @@ func TestSetTargetHighLowTemperatureF(t *testing.T) {
 	})
 
 	t.Run("Invalid high temperature", func(t *testing.T) {
-		err := n.SetTargetHighLowTemperatureF("abc", 100, 72)
+		_, _, err := n.SetTargetHighLowTemperatureF("abc", 100, 72)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -860,7 +861,7 @@ func TestSetTargetHighLowTemperatureF(t *testing.T) {
 	})
 
 	t.Run("Invalid low temperature", func(t *testing.T) {
-		err := n.SetTargetHighLowTemperatureF("abc", 70, 100)
+		_, _, err := n.SetTargetHighLowTemperatureF("abc", 70, 100)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -879,7 +880,7 @@ func TestSetTargetHighLowTemperatureC(t *testing.T) {
 	defer server.Close()
 
 	t.Run("Invalid device id", func(t *testing.T) {
-		err := n.SetTargetHighLowTemperatureC("", 30, 32)
+		_, _, err := n.SetTargetHighLowTemperatureC("", 30, 32)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -893,7 +894,7 @@ func TestSetTargetHighLowTemperatureC(t *testing.T) {
 	})
 
 	t.Run("Invalid high temperature", func(t *testing.T) {
-		err := n.SetTargetHighLowTemperatureC("abc", 50, 32)
+		_, _, err := n.SetTargetHighLowTemperatureC("abc", 50, 32)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -907,7 +908,7 @@ func TestSetTargetHighLowTemperatureC(t *testing.T) {
 	})
 
 	t.Run("Invalid low temperature", func(t *testing.T) {
-		err := n.SetTargetHighLowTemperatureC("abc", 30, 50)
+		_, _, err := n.SetTargetHighLowTemperatureC("abc", 30, 50)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -921,7 +922,7 @@ func TestSetTargetHighLowTemperatureC(t *testing.T) {
 	})
 
 	t.Run("Invalid scale", func(t *testing.T) {
-		err := n.SetTargetHighLowTemperatureC("abc", 30, 32)
+		_, _, err := n.SetTargetHighLowTemperatureC("abc", 30, 32)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -940,14 +941,14 @@ func TestSetHVACMode(t *testing.T) {
 	defer server.Close()
 
 	t.Run("Success", func(t *testing.T) {
-		err := n.SetHVACMode("abc", "heat-cool")
+		_, err := n.SetHVACMode("abc", "heat-cool")
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("Invalid device id", func(t *testing.T) {
-		err := n.SetHVACMode("", "heat-cool")
+		_, err := n.SetHVACMode("", "heat-cool")
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -961,7 +962,7 @@ func TestSetHVACMode(t *testing.T) {
 	})
 
 	t.Run("Invalid mode 1", func(t *testing.T) {
-		err := n.SetHVACMode("abc", "")
+		_, err := n.SetHVACMode("abc", "")
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -975,7 +976,7 @@ func TestSetHVACMode(t *testing.T) {
 	})
 
 	t.Run("Invalid mode 2", func(t *testing.T) {
-		err := n.SetHVACMode("abc", "invalid")
+		_, err := n.SetHVACMode("abc", "invalid")
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -994,14 +995,14 @@ func TestSetThermostatLabel(t *testing.T) {
 	defer server.Close()
 
 	t.Run("Success", func(t *testing.T) {
-		err := n.SetThermostatLabel("abc", "New Label")
+		_, err := n.SetThermostatLabel("abc", "New Label")
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("Invalid device id", func(t *testing.T) {
-		err := n.SetThermostatLabel("", "New label")
+		_, err := n.SetThermostatLabel("", "New label")
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -1015,7 +1016,7 @@ func TestSetThermostatLabel(t *testing.T) {
 	})
 
 	t.Run("Invalid label", func(t *testing.T) {
-		err := n.SetThermostatLabel("abc", "")
+		_, err := n.SetThermostatLabel("abc", "")
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -1034,14 +1035,14 @@ func TestTurnOnFanTimer(t *testing.T) {
 	defer server.Close()
 
 	t.Run("Success", func(t *testing.T) {
-		err := n.TurnOnFanTimer("abc", 15)
+		_, err := n.TurnOnFanTimer("abc", 15)
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("Invalid device id", func(t *testing.T) {
-		err := n.TurnOnFanTimer("", 15)
+		_, err := n.TurnOnFanTimer("", 15)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -1055,7 +1056,7 @@ func TestTurnOnFanTimer(t *testing.T) {
 	})
 
 	t.Run("Invalid fan timer", func(t *testing.T) {
-		err := n.TurnOnFanTimer("abc", 5)
+		_, err := n.TurnOnFanTimer("abc", 5)
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -1074,14 +1075,14 @@ func TestTurnOffFanTimer(t *testing.T) {
 	defer server.Close()
 
 	t.Run("Success", func(t *testing.T) {
-		err := n.TurnOffFanTimer("abc")
+		_, err := n.TurnOffFanTimer("abc")
 		if err != nil {
 			t.Fatal(err)
 		}
 	})
 
 	t.Run("Invalid device id", func(t *testing.T) {
-		err := n.TurnOffFanTimer("")
+		_, err := n.TurnOffFanTimer("")
 		if err == nil {
 			t.Fatal("Expected an error, got nil")
 		}
@@ -1094,3 +1095,37 @@ func TestTurnOffFanTimer(t *testing.T) {
 		}
 	})
 }
+
+func TestGetValueInvalid(t *testing.T) {
+	n, server := createTestErrorConnection(200, `"yes"`)
+	defer server.Close()
+
+	_, err := n.IsThermostatOnline("abc")
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+
+	{
+		expected := "Thermostat is_online is invalid: json: cannot unmarshal string into Go value of type bool"
+		if err.Error() != expected {
+			t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+		}
+	}
+}
+
+func TestGetValueNull(t *testing.T) {
+	n, server := createTestErrorConnection(200, `null`)
+	defer server.Close()
+
+	_, err := n.IsThermostatOnline("abc")
+	if err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+
+	{
+		expected := "Thermostat is_online not found"
+		if err.Error() != expected {
+			t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+		}
+	}
+}
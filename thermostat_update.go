@@ -0,0 +1,200 @@
+package nest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ThermostatUpdate collects changes to a thermostat so that they can be written together
+// with UpdateThermostat, using a single request and a single write from the rate limit.
+// Only the changes that are set are written.
+type ThermostatUpdate struct {
+	mode      *HVACMode
+	target    *Temperature
+	low, high *Temperature
+	fanTimer  *int
+	label     *string
+	scale     *TemperatureScale
+}
+
+// NewThermostatUpdate returns an empty thermostat update
+func NewThermostatUpdate() *ThermostatUpdate {
+	return &ThermostatUpdate{}
+}
+
+// HVACMode changes the HVAC mode
+func (u *ThermostatUpdate) HVACMode(mode HVACMode) *ThermostatUpdate {
+	u.mode = &mode
+	return u
+}
+
+// TargetTemperature changes the target temperature, used in heat or cool mode
+func (u *ThermostatUpdate) TargetTemperature(temp Temperature) *ThermostatUpdate {
+	u.target = &temp
+	return u
+}
+
+// TargetRange changes the target low and high temperatures, used in heat-cool mode
+func (u *ThermostatUpdate) TargetRange(low, high Temperature) *ThermostatUpdate {
+	u.low, u.high = &low, &high
+	return u
+}
+
+// FanTimer turns on the fan timer for duration minutes
+func (u *ThermostatUpdate) FanTimer(duration int) *ThermostatUpdate {
+	u.fanTimer = &duration
+	return u
+}
+
+// FanTimerOff turns off the fan timer
+func (u *ThermostatUpdate) FanTimerOff() *ThermostatUpdate {
+	off := 0
+	u.fanTimer = &off
+	return u
+}
+
+// Label changes the label
+func (u *ThermostatUpdate) Label(label string) *ThermostatUpdate {
+	u.label = &label
+	return u
+}
+
+// TemperatureScale changes the temperature scale the thermostat displays
+func (u *ThermostatUpdate) TemperatureScale(scale TemperatureScale) *ThermostatUpdate {
+	u.scale = &scale
+	return u
+}
+
+// UpdateThermostat validates the changes in u as a whole and writes them to the specified
+// thermostat with a single request. When setpoints or the fan timer are changed the
+// thermostat is fetched first, and setpoints are validated against the HVAC mode the
+// thermostat will be in after the update. It returns the names of the fields Nest
+// applied, e.g. hvac_mode and target_temperature_f.
+func (n *Connection) UpdateThermostat(deviceID string, u *ThermostatUpdate) ([]string, error) {
+	// Error checking
+	if u == nil || *u == (ThermostatUpdate{}) {
+		return nil, errors.New("Thermostat update must not be empty")
+	}
+
+	if strings.Trim(deviceID, " ") == "" {
+		return nil, errors.New("Device ID must not be empty")
+	}
+
+	if u.target != nil && u.high != nil {
+		return nil, errors.New("Target Temperature and target range must not be changed together")
+	}
+
+	req := thermostatRequest{}
+
+	if u.mode != nil {
+		err := u.mode.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		req.HVACMode = *u.mode
+	}
+
+	if u.target != nil {
+		err := u.target.validate("Target Temperature")
+		if err != nil {
+			return nil, err
+		}
+
+		req.setTargetTemperature(*u.target)
+	}
+
+	if u.high != nil {
+		err := validateRange(*u.low, *u.high)
+		if err != nil {
+			return nil, err
+		}
+
+		req.setTargetRange(*u.low, *u.high)
+	}
+
+	if u.fanTimer != nil {
+		if *u.fanTimer != 0 {
+			err := validateFanTimerDuration(*u.fanTimer)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		req.setFanTimer(*u.fanTimer)
+	}
+
+	if u.label != nil {
+		if strings.Trim(*u.label, " ") == "" {
+			return nil, errors.New("Label must not be empty")
+		}
+
+		req.Label = *u.label
+	}
+
+	if u.scale != nil {
+		err := u.scale.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		req.TemperatureScale = *u.scale
+	}
+
+	if u.target != nil || u.high != nil || (u.fanTimer != nil && *u.fanTimer != 0) {
+		err := n.validateUpdate(deviceID, u)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := n.putValue("thermostats", deviceID, "", req)
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[string]json.RawMessage)
+
+	err = json.Unmarshal(data, &applied)
+	if err != nil {
+		return nil, fmt.Errorf("Thermostat update response is invalid: %s", err)
+	}
+
+	fields := []string{}
+	for field := range applied {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return fields, nil
+}
+
+// validateUpdate fetches the thermostat and checks that it can accept the update
+func (n *Connection) validateUpdate(deviceID string, u *ThermostatUpdate) error {
+	thermostat, err := n.GetThermostat(deviceID)
+	if err != nil {
+		return err
+	}
+
+	if u.fanTimer != nil && *u.fanTimer != 0 && !thermostat.HasFan {
+		return fmt.Errorf("Thermostat %s doesn't have a fan", deviceID)
+	}
+
+	// Setpoints are checked against the mode the thermostat is changing to
+	if u.mode != nil {
+		thermostat.HVACMode = *u.mode
+	}
+
+	if u.target != nil {
+		return thermostat.ValidateTargetTemperature(*u.target)
+	}
+
+	if u.high != nil {
+		return thermostat.ValidateTargetRange(*u.low, *u.high)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package nest
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUpdateThermostat(t *testing.T) {
+	n, server, write := createTestWriteConnection(nil)
+	defer server.Close()
+
+	t.Run("Mode and target temperature", func(t *testing.T) {
+		fields, err := n.UpdateThermostat("abc", NewThermostatUpdate().
+			HVACMode(HVACModeHeat).
+			TargetTemperature(Fahrenheit(70)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := []string{"hvac_mode", "target_temperature_f"}
+			if !reflect.DeepEqual(fields, expected) {
+				t.Fatalf("Expected fields to equal %v, got %v", expected, fields)
+			}
+		}
+
+		for _, expected := range []string{`"hvac_mode":"heat"`, `"target_temperature_f":70`} {
+			if !strings.Contains(write.body, expected) {
+				t.Fatalf("Expected body to contain %s, got %s", expected, write.body)
+			}
+		}
+	})
+
+	t.Run("Range, fan timer, label and scale", func(t *testing.T) {
+		fields, err := n.UpdateThermostat("abc", NewThermostatUpdate().
+			TargetRange(Celsius(20), Celsius(23)).
+			FanTimer(30).
+			Label("Hallway").
+			TemperatureScale(TemperatureScaleC))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := []string{"fan_timer_active", "fan_timer_duration", "label", "target_temperature_high_c", "target_temperature_low_c", "temperature_scale"}
+			if !reflect.DeepEqual(fields, expected) {
+				t.Fatalf("Expected fields to equal %v, got %v", expected, fields)
+			}
+		}
+	})
+
+	t.Run("Fan timer off", func(t *testing.T) {
+		fields, err := n.UpdateThermostat("def", NewThermostatUpdate().FanTimerOff())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := []string{"fan_timer_active"}
+			if !reflect.DeepEqual(fields, expected) {
+				t.Fatalf("Expected fields to equal %v, got %v", expected, fields)
+			}
+		}
+	})
+
+	t.Run("Setpoint rejected by new mode", func(t *testing.T) {
+		_, err := n.UpdateThermostat("abc", NewThermostatUpdate().
+			HVACMode(HVACModeEco).
+			TargetRange(Fahrenheit(68), Fahrenheit(74)))
+		if !errors.Is(err, ErrSetpointMode) {
+			t.Fatalf("Expected errors.Is(%v), got %v", ErrSetpointMode, err)
+		}
+	})
+
+	t.Run("Thermostat without a fan", func(t *testing.T) {
+		_, err := n.UpdateThermostat("def", NewThermostatUpdate().FanTimer(15))
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Thermostat def doesn't have a fan"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Target temperature and range", func(t *testing.T) {
+		_, err := n.UpdateThermostat("abc", NewThermostatUpdate().
+			TargetTemperature(Fahrenheit(70)).
+			TargetRange(Fahrenheit(68), Fahrenheit(74)))
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Target Temperature and target range must not be changed together"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Empty update", func(t *testing.T) {
+		_, err := n.UpdateThermostat("abc", NewThermostatUpdate())
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Thermostat update must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+
+	t.Run("Invalid device id", func(t *testing.T) {
+		_, err := n.UpdateThermostat("", NewThermostatUpdate().Label("Hallway"))
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+
+		{
+			expected := "Device ID must not be empty"
+			if err.Error() != expected {
+				t.Fatalf("Expected error message to equal %s, got %s", expected, err.Error())
+			}
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package nest
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// timestamp decodes an ISO 8601 timestamp, treating empty and null values as the zero time
+type timestamp struct {
+	time.Time
+}
+
+func (t *timestamp) UnmarshalJSON(data []byte) error {
+	var str string
+
+	err := json.Unmarshal(data, &str)
+	if err != nil {
+		// null values leave str empty without an error
+		return err
+	}
+
+	if str == "" {
+		t.Time = time.Time{}
+		return nil
+	}
+
+	t.Time, err = time.Parse(time.RFC3339Nano, str)
+
+	return err
+}
@@ -0,0 +1,150 @@
+package nest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTimestampFields(t *testing.T) {
+	t.Run("Thermostat", func(t *testing.T) {
+		thermostat := Thermostat{}
+
+		err := json.Unmarshal([]byte(`{"device_id":"abc","last_connection":"2019-01-02T14:27:53.729Z","fan_timer_timeout":""}`), &thermostat)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := time.Date(2019, 1, 2, 14, 27, 53, 729000000, time.UTC)
+			if !thermostat.LastConnection.Equal(expected) {
+				t.Fatalf("Expected LastConnection to equal %v, got %v", expected, thermostat.LastConnection)
+			}
+		}
+
+		if !thermostat.FanTimerTimeout.IsZero() {
+			t.Fatalf("Expected FanTimerTimeout to be zero, got %v", thermostat.FanTimerTimeout)
+		}
+
+		{
+			expected := "abc"
+			if thermostat.DeviceID != expected {
+				t.Fatalf("Expected DeviceID to equal %s, got %s", expected, thermostat.DeviceID)
+			}
+		}
+	})
+
+	t.Run("Smoke/CO Alarm", func(t *testing.T) {
+		smokeCOAlarm := SmokeCOAlarm{}
+
+		err := json.Unmarshal([]byte(`{"device_id":"abc","last_manual_test_time":"2019-01-01T14:27:53.729Z"}`), &smokeCOAlarm)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := time.Date(2019, 1, 1, 14, 27, 53, 729000000, time.UTC)
+			if !smokeCOAlarm.LastManualTestTime.Equal(expected) {
+				t.Fatalf("Expected LastManualTestTime to equal %v, got %v", expected, smokeCOAlarm.LastManualTestTime)
+			}
+		}
+
+		if !smokeCOAlarm.LastConnection.IsZero() {
+			t.Fatalf("Expected LastConnection to be zero, got %v", smokeCOAlarm.LastConnection)
+		}
+	})
+
+	t.Run("Camera", func(t *testing.T) {
+		camera := Camera{}
+
+		err := json.Unmarshal([]byte(`{"last_is_online_change":"2016-12-29T18:42:00.000Z","last_event":{"start_time":"2016-12-29T00:00:00.000Z","end_time":null}}`), &camera)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := time.Date(2016, 12, 29, 18, 42, 0, 0, time.UTC)
+			if !camera.LastIsOnlineChange.Equal(expected) {
+				t.Fatalf("Expected LastIsOnlineChange to equal %v, got %v", expected, camera.LastIsOnlineChange)
+			}
+		}
+
+		{
+			expected := time.Date(2016, 12, 29, 0, 0, 0, 0, time.UTC)
+			if !camera.LastEvent.StartTime.Equal(expected) {
+				t.Fatalf("Expected StartTime to equal %v, got %v", expected, camera.LastEvent.StartTime)
+			}
+		}
+
+		if !camera.LastEvent.EndTime.IsZero() {
+			t.Fatalf("Expected EndTime to be zero, got %v", camera.LastEvent.EndTime)
+		}
+	})
+
+	t.Run("Structure", func(t *testing.T) {
+		structure := Structure{}
+
+		err := json.Unmarshal([]byte(`{"structure_id":"abc123","eta_begin":"2016-12-29T18:42:00.000Z"}`), &structure)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		{
+			expected := time.Date(2016, 12, 29, 18, 42, 0, 0, time.UTC)
+			if !structure.ETABegin.Equal(expected) {
+				t.Fatalf("Expected ETABegin to equal %v, got %v", expected, structure.ETABegin)
+			}
+		}
+
+		if !structure.PeakPeriodStartTime.IsZero() {
+			t.Fatalf("Expected PeakPeriodStartTime to be zero, got %v", structure.PeakPeriodStartTime)
+		}
+	})
+
+	t.Run("Invalid timestamp", func(t *testing.T) {
+		thermostat := Thermostat{}
+
+		err := json.Unmarshal([]byte(`{"last_connection":"yesterday"}`), &thermostat)
+		if err == nil {
+			t.Fatal("Expected an error, got nil")
+		}
+	})
+}
+
+func TestTimestampGetters(t *testing.T) {
+	// The API returns an empty string for devices that have never connected
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`""`))
+	}))
+	defer server.Close()
+
+	n := Connection{
+		AccessToken: "TEST",
+		testURL:     fmt.Sprintf("%s/devices", server.URL),
+	}
+
+	t.Run("Thermostat", func(t *testing.T) {
+		lastConn, err := n.GetThermostatLastConnection("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !lastConn.IsZero() {
+			t.Fatalf("Expected last connection to be zero, got %v", lastConn)
+		}
+	})
+
+	t.Run("Smoke/CO Alarm", func(t *testing.T) {
+		lastConn, err := n.GetSmokeCOAlarmLastConnection("abc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !lastConn.IsZero() {
+			t.Fatalf("Expected last connection to be zero, got %v", lastConn)
+		}
+	})
+}
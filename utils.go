@@ -1,22 +1,18 @@
 package nest
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
-	"net/http"
-	"reflect"
 	"strings"
+	"time"
 )
 
-type errorResponse struct {
-	Error    string `json:"error"`
-	Type     string `json:"type"`
-	Message  string `json:"message"`
-	Instance string `json:"instance"`
-}
+// Format of the timestamps used by the API
+const timeFormat = "2006-01-02T15:04:05.000Z"
 
 func (n *Connection) setURL(endpoint string) string {
 	url := fmt.Sprintf("%s/%s", BaseURL, endpoint)
@@ -29,29 +25,107 @@ func (n *Connection) setURL(endpoint string) string {
 	return url
 }
 
+// rootURL returns the URL of the top of the data model, which contains
+// devices, structures and metadata
+func (n *Connection) rootURL() string {
+	url := BaseURL
+
+	// Base URL to use for tests
+	if n.testURL != "" {
+		url = n.testURL
+	}
+
+	return strings.TrimSuffix(url, "/devices")
+}
+
+// execute makes a request, serving reads from the connection's cache when it has a recent
+// enough response
 func (n *Connection) execute(url, method string, body io.Reader) ([]byte, error) {
-	req, err := http.NewRequest(method, url, body)
+	path, cacheable := n.cachePath(url)
+	cacheable = cacheable && n.Cache != nil && method == "GET"
+
+	if cacheable {
+		if data, ok := n.Cache.get(path, time.Now()); ok {
+			return data, nil
+		}
+	}
+
+	// The response is as of when the request started, a write made while it's in flight
+	// may not be in it
+	start := time.Now()
+
+	data, err := n.send(url, method, body)
 	if err != nil {
 		return []byte{}, err
 	}
 
-	req.Header.Add("Authorization", fmt.Sprintf("Bearer %s", n.AccessToken))
+	if cacheable {
+		n.Cache.set(path, data, start)
+	}
+
+	return data, nil
+}
+
+// send makes a request, retrying it according to the connection's retry policy
+func (n *Connection) send(url, method string, body io.Reader) ([]byte, error) {
+	// Keep the body so it can be sent again if the request is retried
+	var bodyData []byte
+	if body != nil {
+		var err error
+
+		bodyData, err = ioutil.ReadAll(body)
+		if err != nil {
+			return []byte{}, err
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		var reqBody io.Reader
+		if bodyData != nil {
+			reqBody = bytes.NewReader(bodyData)
+		}
+
+		data, err := n.executeOnce(url, method, reqBody)
+		if err == nil {
+			return data, nil
+		}
+
+		wait, retry := n.Retry.shouldRetry(method, attempt, err)
+		if !retry {
+			return []byte{}, err
+		}
+
+		if n.Retry.OnRetry != nil {
+			n.Retry.OnRetry(RetryEvent{
+				Attempt: attempt,
+				Method:  method,
+				URL:     url,
+				Err:     err,
+				Wait:    wait,
+			})
+		}
 
-	// Need to create a custom client because defualt http client
-	// doesn't forward headers when a redirect 3xx is received
-	client := &http.Client{
-		CheckRedirect: func(redirRequest *http.Request, via []*http.Request) error {
-			redirRequest.Header = req.Header
+		select {
+		case <-n.Context().Done():
+			return []byte{}, n.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
 
-			if len(via) >= 10 {
-				return errors.New("stopped after 10 redirects")
-			}
-			return nil
-		},
+func (n *Connection) executeOnce(url, method string, body io.Reader) ([]byte, error) {
+	req, err := n.newRequest(n.Context(), method, url, body)
+	if err != nil {
+		return []byte{}, err
 	}
 
-	resp, err := client.Do(req)
+	resp, err := n.newClient().Do(req)
 	if err != nil {
+		// Report cancellation as the context's own error
+		if ctxErr := n.Context().Err(); ctxErr != nil {
+			return []byte{}, ctxErr
+		}
+
 		return []byte{}, err
 	}
 	defer resp.Body.Close()
@@ -62,91 +136,148 @@ func (n *Connection) execute(url, method string, body io.Reader) ([]byte, error)
 	}
 
 	// Check for errors
-	if resp.StatusCode != 200 {
-		errMsg := errorResponse{}
-
-		err = json.Unmarshal(data, &errMsg)
-		if err != nil {
-			return []byte{}, fmt.Errorf("Error: %s", string(data))
-		}
-
-		return []byte{}, fmt.Errorf("Error: %s", errMsg.Message)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return []byte{}, newAPIError(resp, data, url)
 	}
 
 	return data, nil
 }
 
-func (n *Connection) getValue(deviceType, deviceID, field string) (string, error) {
+// getValue reads a single field of a device and decodes its JSON value into v
+func (n *Connection) getValue(deviceType, deviceID, field string, v interface{}) error {
 	// Error checking
 	if strings.Trim(deviceID, " ") == "" {
 		if deviceType == "structures" {
-			return "", errors.New("Structure ID must not be empty")
+			return errors.New("Structure ID must not be empty")
 		}
 
-		return "", errors.New("Device ID must not be empty")
+		return errors.New("Device ID must not be empty")
 	}
 
 	url := n.setURL(fmt.Sprintf("%s/%s/%s", deviceType, deviceID, field))
 
 	data, err := n.execute(url, "GET", nil)
 	if err != nil {
-		return "", err
+		return err
 	}
 
-	// Thermostat field not found
-	if len(data) == 0 {
-		return "", fmt.Errorf("%s %s not found", n.toTitleCase(deviceType), field)
+	// Field not found, which the API responds to with an empty body or null
+	if len(data) == 0 || string(bytes.TrimSpace(data)) == "null" {
+		return fmt.Errorf("%s %s not found", n.toTitleCase(deviceType), field)
+	}
+
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return fmt.Errorf("%s %s is invalid: %s", n.toTitleCase(deviceType), field, err)
 	}
 
-	return string(data), nil
+	return nil
 }
 
-func (n *Connection) setValue(deviceType, deviceID string, vals map[string]interface{}) error {
-	// Error checking
-	if strings.Trim(deviceID, " ") == "" {
-		return errors.New("Device ID must not be empty")
+// setValue writes payload to the device and decodes the values Nest applied, which it
+// echoes in the response, into confirmed unless it's nil. It returns an error if any
+// written field isn't in the response or is null.
+func (n *Connection) setValue(deviceType, deviceID string, payload, confirmed interface{}) error {
+	return n.setFieldValue(deviceType, deviceID, "", payload, confirmed)
+}
+
+// setFieldValue writes payload like setValue, to a field of the device, or to the device
+// itself if field is empty
+func (n *Connection) setFieldValue(deviceType, deviceID, field string, payload, confirmed interface{}) error {
+	data, err := n.putValue(deviceType, deviceID, field, payload)
+	if err != nil {
+		return err
 	}
 
-	url := n.setURL(fmt.Sprintf("%s/%s", deviceType, deviceID))
+	applied := make(map[string]json.RawMessage)
+
+	err = json.Unmarshal(data, &applied)
+	if err != nil {
+		return fmt.Errorf("%s %s response is invalid: %s", n.toTitleCase(deviceType), deviceID, err)
+	}
+
+	if confirmed != nil {
+		err = json.Unmarshal(data, confirmed)
+		if err != nil {
+			return fmt.Errorf("%s %s response is invalid: %s", n.toTitleCase(deviceType), deviceID, err)
+		}
+	}
 
-	body := strings.NewReader(n.formatMap(vals))
+	// The payload is encoded again to find the names of the fields that were written
+	written := make(map[string]json.RawMessage)
 
-	data, err := n.execute(url, "PUT", body)
+	body, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
 
-	// Thermostat field not found
-	if len(data) == 0 {
-		return fmt.Errorf("Thermostat %s not found", deviceID)
+	err = json.Unmarshal(body, &written)
+	if err != nil {
+		return err
+	}
+
+	for name := range written {
+		if val, ok := applied[name]; !ok || string(val) == "null" {
+			return fmt.Errorf("%s %s did not confirm %s", n.toTitleCase(deviceType), deviceID, name)
+		}
 	}
 
 	return nil
 }
 
-// formatMap formats a map of string keys and interface{} values as a JSON string
-func (n *Connection) formatMap(vals map[string]interface{}) string {
-	str := "{"
-
-	for key, val := range vals {
-		str += fmt.Sprintf("\"%s\":", key)
-
-		dataType := reflect.ValueOf(val)
-		switch dataType.Kind() {
-		case reflect.String:
-			str += fmt.Sprintf("\"%s\",", val)
-		case reflect.Int, reflect.Float32:
-			str += fmt.Sprintf("%d,", val)
-		default:
-			str += fmt.Sprintf("\"%v\",", val)
+// putValue writes payload, encoded as JSON, to a field of the device, or to the device
+// itself if field is empty, and returns the response, which holds the values Nest applied
+func (n *Connection) putValue(deviceType, deviceID, field string, payload interface{}) ([]byte, error) {
+	// Error checking
+	if strings.Trim(deviceID, " ") == "" {
+		if deviceType == "structures" {
+			return nil, errors.New("Structure ID must not be empty")
 		}
+
+		return nil, errors.New("Device ID must not be empty")
+	}
+
+	err := n.limitWrite(deviceType, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	url := n.setURL(fmt.Sprintf("%s/%s", deviceType, deviceID))
+	if field != "" {
+		url = fmt.Sprintf("%s/%s", url, field)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
 	}
 
-	// Remove last comma and add closing curly brace
-	str = str[:len(str)-1]
-	str += "}"
+	data, err := n.execute(url, "PUT", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
 
-	return str
+	// Nest can change other fields of the device too, so the whole device is read again
+	if n.Cache != nil {
+		if path, ok := n.cachePath(n.setURL(fmt.Sprintf("%s/%s", deviceType, deviceID))); ok {
+			n.Cache.invalidate(path, time.Now())
+		}
+	}
+
+	// Device not found
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%s %s not found", n.toTitleCase(deviceType), deviceID)
+	}
+
+	// Fields such as a structure's eta can be written but not read back
+	if n.Confirm != nil && field == "" {
+		err = n.waitForValues(deviceType, deviceID, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
 }
 
 func (n *Connection) toTitleCase(str string) string {
@@ -0,0 +1,162 @@
+package nest
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Reasons a thermostat won't accept a target temperature, wrapped by SetpointError
+var (
+	ErrSetpointMode     = errors.New("Target temperature can't be set in the current HVAC mode")
+	ErrSetpointCapacity = errors.New("Thermostat can't heat or cool as required")
+	ErrSetpointRange    = errors.New("Target temperature range is invalid")
+	ErrSetpointLocked   = errors.New("Target temperature is outside the locked range")
+)
+
+// Smallest gap Nest allows between the low and high target temperatures in heat-cool mode
+const (
+	minRangeGapF = 3
+	minRangeGapC = 1.5
+)
+
+// SetpointError is returned when a thermostat can't accept a target temperature, before
+// anything is sent to Nest. Reason is one of the ErrSetpoint errors, and errors.Is also
+// matches ErrValidation like the equivalent error from the API would.
+type SetpointError struct {
+	DeviceID string
+	Reason   error
+	Message  string
+}
+
+func (e *SetpointError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the reason the setpoint was rejected
+func (e *SetpointError) Unwrap() error {
+	return e.Reason
+}
+
+// Is reports whether target is ErrValidation
+func (e *SetpointError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+func (t Thermostat) setpointError(reason error, format string, a ...interface{}) error {
+	return &SetpointError{
+		DeviceID: t.DeviceID,
+		Reason:   reason,
+		Message:  fmt.Sprintf(format, a...),
+	}
+}
+
+// ValidateTargetTemperature returns an error unless the thermostat, in its current state,
+// accepts temp as its target temperature. It doesn't contact Nest, so it can be used with
+// a thermostat that was fetched earlier or received from a stream.
+func (t Thermostat) ValidateTargetTemperature(temp Temperature) error {
+	err := temp.validate("Target Temperature")
+	if err != nil {
+		return err
+	}
+
+	switch t.HVACMode {
+	case HVACModeHeat:
+		if !t.CanHeat {
+			return t.setpointError(ErrSetpointCapacity, "Thermostat %s can't heat", t.DeviceID)
+		}
+	case HVACModeCool:
+		if !t.CanCool {
+			return t.setpointError(ErrSetpointCapacity, "Thermostat %s can't cool", t.DeviceID)
+		}
+	case HVACModeHeatCool:
+		return t.setpointError(ErrSetpointMode, "Target Temperature can't be set while HVAC Mode is heat-cool, set the target range instead")
+	default:
+		return t.setpointError(ErrSetpointMode, "Target Temperature can't be set while HVAC Mode is %s", t.HVACMode)
+	}
+
+	return t.validateLocked("Target Temperature", temp)
+}
+
+// ValidateTargetRange returns an error unless the thermostat, in its current state,
+// accepts low and high as its target temperatures in heat-cool mode. It doesn't contact
+// Nest, so it can be used with a thermostat that was fetched earlier or received from a
+// stream.
+func (t Thermostat) ValidateTargetRange(low, high Temperature) error {
+	err := validateRange(low, high)
+	if err != nil {
+		return err
+	}
+
+	if !t.CanHeat || !t.CanCool {
+		return t.setpointError(ErrSetpointCapacity, "Thermostat %s must be able to heat and cool to set a target range", t.DeviceID)
+	}
+
+	if t.HVACMode != HVACModeHeatCool {
+		return t.setpointError(ErrSetpointMode, "Target range can't be set while HVAC Mode is %s", t.HVACMode)
+	}
+
+	err = t.validateLocked("Target Low Temperature", low)
+	if err != nil {
+		return err
+	}
+
+	return t.validateLocked("Target High Temperature", high)
+}
+
+// validateRange returns an error unless low and high are valid target temperatures that
+// are far enough apart for heat-cool mode
+func validateRange(low, high Temperature) error {
+	err := low.validate("Target Low Temperature")
+	if err != nil {
+		return err
+	}
+
+	err = high.validate("Target High Temperature")
+	if err != nil {
+		return err
+	}
+
+	low = low.In(high.Scale)
+	high = high.In(high.Scale)
+
+	if low.Value >= high.Value {
+		return &SetpointError{
+			Reason:  ErrSetpointRange,
+			Message: "Target Low Temperature must be lower than Target High Temperature",
+		}
+	}
+
+	gap := Fahrenheit(minRangeGapF)
+	if high.Scale == TemperatureScaleC {
+		gap = Celsius(minRangeGapC)
+	}
+
+	if high.Value-low.Value < gap.Value {
+		return &SetpointError{
+			Reason:  ErrSetpointRange,
+			Message: fmt.Sprintf("Target High Temperature must be at least %s above Target Low Temperature", gap),
+		}
+	}
+
+	return nil
+}
+
+// validateLocked returns an error if the thermostat is locked and temp is outside the
+// locked range
+func (t Thermostat) validateLocked(name string, temp Temperature) error {
+	if !t.IsLocked {
+		return nil
+	}
+
+	min, max := Fahrenheit(float64(t.LockedTempMinF)), Fahrenheit(float64(t.LockedTempMaxF))
+	if temp.Scale == TemperatureScaleC {
+		min, max = Celsius(float64(t.LockedTempMinC)), Celsius(float64(t.LockedTempMaxC))
+	}
+
+	temp = temp.In(temp.Scale)
+	if temp.Value < min.Value || temp.Value > max.Value {
+		return t.setpointError(ErrSetpointLocked, "%s must be in the locked range of %g - %g", name, min.Value, max.Value)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package nest
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateTargetTemperature(t *testing.T) {
+	heating := Thermostat{
+		DeviceID:       "abc",
+		CanHeat:        true,
+		HVACMode:       HVACModeHeat,
+		LockedTempMinF: 68,
+		LockedTempMaxF: 72,
+		LockedTempMinC: 20,
+		LockedTempMaxC: 22,
+	}
+
+	locked := heating
+	locked.IsLocked = true
+
+	cooling := heating
+	cooling.HVACMode = HVACModeCool
+
+	tests := []struct {
+		name       string
+		thermostat Thermostat
+		temp       Temperature
+		expected   error
+		message    string
+	}{
+		{"Heat mode", heating, Fahrenheit(75), nil, ""},
+		{"Locked in range", locked, Celsius(21.5), nil, ""},
+		{"Locked out of range", locked, Fahrenheit(75), ErrSetpointLocked, "Target Temperature must be in the locked range of 68 - 72"},
+		{"Can't cool", cooling, Fahrenheit(75), ErrSetpointCapacity, "Thermostat abc can't cool"},
+		{"Eco mode", Thermostat{HVACMode: HVACModeEco}, Fahrenheit(75), ErrSetpointMode, "Target Temperature can't be set while HVAC Mode is eco"},
+		{"Off", Thermostat{HVACMode: HVACModeOff}, Fahrenheit(75), ErrSetpointMode, "Target Temperature can't be set while HVAC Mode is off"},
+		{"Heat-cool mode", Thermostat{HVACMode: HVACModeHeatCool}, Fahrenheit(75), ErrSetpointMode, "Target Temperature can't be set while HVAC Mode is heat-cool, set the target range instead"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.thermostat.ValidateTargetTemperature(test.temp)
+			if test.expected == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if !errors.Is(err, test.expected) || !errors.Is(err, ErrValidation) {
+				t.Fatalf("Expected errors.Is(%v) and errors.Is(%v), got %v", test.expected, ErrValidation, err)
+			}
+
+			if err.Error() != test.message {
+				t.Fatalf("Expected error message to equal %s, got %s", test.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidateTargetRange(t *testing.T) {
+	heatCool := Thermostat{
+		DeviceID:       "abc",
+		CanHeat:        true,
+		CanCool:        true,
+		HVACMode:       HVACModeHeatCool,
+		LockedTempMinF: 68,
+		LockedTempMaxF: 76,
+	}
+
+	locked := heatCool
+	locked.IsLocked = true
+
+	heatOnly := heatCool
+	heatOnly.CanCool = false
+
+	tests := []struct {
+		name       string
+		thermostat Thermostat
+		low, high  Temperature
+		expected   error
+		message    string
+	}{
+		{"Valid", heatCool, Fahrenheit(68), Fahrenheit(74), nil, ""},
+		{"Minimum gap", heatCool, Celsius(20), Celsius(21.5), nil, ""},
+		{"Low above high", heatCool, Fahrenheit(74), Fahrenheit(68), ErrSetpointRange, "Target Low Temperature must be lower than Target High Temperature"},
+		{"Gap too small", heatCool, Fahrenheit(70), Fahrenheit(72), ErrSetpointRange, "Target High Temperature must be at least 3°F above Target Low Temperature"},
+		{"Gap too small in C", heatCool, Celsius(20), Celsius(21), ErrSetpointRange, "Target High Temperature must be at least 1.5°C above Target Low Temperature"},
+		{"Can't cool", heatOnly, Fahrenheit(68), Fahrenheit(74), ErrSetpointCapacity, "Thermostat abc must be able to heat and cool to set a target range"},
+		{"Heat mode", Thermostat{CanHeat: true, CanCool: true, HVACMode: HVACModeHeat}, Fahrenheit(68), Fahrenheit(74), ErrSetpointMode, "Target range can't be set while HVAC Mode is heat"},
+		{"Locked out of range", locked, Fahrenheit(66), Fahrenheit(74), ErrSetpointLocked, "Target Low Temperature must be in the locked range of 68 - 76"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.thermostat.ValidateTargetRange(test.low, test.high)
+			if test.expected == nil {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			if !errors.Is(err, test.expected) || !errors.Is(err, ErrValidation) {
+				t.Fatalf("Expected errors.Is(%v) and errors.Is(%v), got %v", test.expected, ErrValidation, err)
+			}
+
+			if err.Error() != test.message {
+				t.Fatalf("Expected error message to equal %s, got %s", test.message, err.Error())
+			}
+		})
+	}
+}
+
+func TestSetpointValidatedBeforeWrite(t *testing.T) {
+	n, server, write := createTestWriteConnection(nil)
+	defer server.Close()
+
+	t.Run("Heat-cool thermostat", func(t *testing.T) {
+		_, err := n.SetTargetTemperatureF("abc", 70)
+		if !errors.Is(err, ErrSetpointMode) {
+			t.Fatalf("Expected errors.Is(%v), got %v", ErrSetpointMode, err)
+		}
+	})
+
+	t.Run("Locked thermostat", func(t *testing.T) {
+		_, err := n.SetTargetTemperature("def", Fahrenheit(80))
+		if !errors.Is(err, ErrSetpointLocked) {
+			t.Fatalf("Expected errors.Is(%v), got %v", ErrSetpointLocked, err)
+		}
+	})
+
+	t.Run("Thermostat can't cool", func(t *testing.T) {
+		_, _, err := n.SetTargetHighLowTemperatureF("def", 74, 70)
+		if !errors.Is(err, ErrSetpointCapacity) {
+			t.Fatalf("Expected errors.Is(%v), got %v", ErrSetpointCapacity, err)
+		}
+	})
+
+	{
+		expected := ""
+		if write.body != expected {
+			t.Fatalf("Expected nothing to be written, got %s", write.body)
+		}
+	}
+}